@@ -0,0 +1,100 @@
+package annot
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	anchorOpen  = "{{"
+	anchorClose = "}}"
+)
+
+// Anchor is a named span of columns in a line.
+type Anchor struct {
+	// Col is the first column of the span.
+	Col int
+
+	// ColEnd is the last column of the span. It is 0 if the span
+	// is only one column wide.
+	ColEnd int
+}
+
+// Anchors maps anchor names to spans of a line. Annotations can reference
+// an anchor by its name instead of hard-coded column numbers.
+type Anchors map[string]Anchor
+
+// ParseAnchors removes all anchor markers of the form {{name:text}} from
+// marked and returns the resulting line together with the anchors. The
+// span of an anchor covers the display width of text. An empty text
+// creates an anchor at the column where the marker was.
+func ParseAnchors(marked string) (string, Anchors, error) {
+	anchors := Anchors{}
+	b := &strings.Builder{}
+	col := 0
+
+	rest := marked
+	for {
+		openIdx := strings.Index(rest, anchorOpen)
+		if openIdx == -1 {
+			b.WriteString(rest)
+			return b.String(), anchors, nil
+		}
+		b.WriteString(rest[:openIdx])
+		col += uniseg.StringWidth(rest[:openIdx])
+
+		markerPos := len(marked) - len(rest) + openIdx
+		rest = rest[openIdx+len(anchorOpen):]
+
+		closeIdx := strings.Index(rest, anchorClose)
+		if closeIdx == -1 {
+			return "", nil, newAnchorSyntaxError(markerPos, "is not closed")
+		}
+		name, text, found := strings.Cut(rest[:closeIdx], ":")
+		if !found {
+			return "", nil, newAnchorSyntaxError(markerPos, "has no colon after the name")
+		}
+		if name == "" {
+			return "", nil, newAnchorSyntaxError(markerPos, "has an empty name")
+		}
+		if _, ok := anchors[name]; ok {
+			return "", nil, newAnchorSyntaxError(markerPos, "redefines name "+name)
+		}
+
+		width := uniseg.StringWidth(text)
+		anchor := Anchor{Col: col}
+		if width > 1 {
+			anchor.ColEnd = col + width - 1
+		}
+		anchors[name] = anchor
+
+		b.WriteString(text)
+		col += width
+		rest = rest[closeIdx+len(anchorClose):]
+	}
+}
+
+// Annot returns an annotation with lines for the anchor called name.
+func (as Anchors) Annot(name string, lines ...string) (*Annot, error) {
+	anchor, ok := as[name]
+	if !ok {
+		return nil, newUnknownAnchorError(name)
+	}
+	return anchor.Annot(lines...), nil
+}
+
+// Annot returns an annotation with lines for the span of the anchor.
+func (a Anchor) Annot(lines ...string) *Annot {
+	return &Annot{Col: a.Col, ColEnd: a.ColEnd, Lines: lines}
+}
+
+// spanAnchor returns the anchor of the columns of the bytes from start to
+// the exclusive end of line, in which a tab counts as one column.
+func spanAnchor(line string, start, end int) Anchor {
+	anchor := Anchor{Col: displayCol(line, start)}
+	if width := sourceWidth(line[start:end]); width > 1 {
+		anchor.ColEnd = anchor.Col + width - 1
+	}
+	return anchor
+}
@@ -0,0 +1,107 @@
+package annot
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseAnchors(t *testing.T) {
+	tests := []struct {
+		name        string
+		marked      string
+		wantLine    string
+		wantAnchors Anchors
+		wantErr     error
+	}{
+		{
+			name:        "no anchors",
+			marked:      "plain line",
+			wantLine:    "plain line",
+			wantAnchors: Anchors{},
+		},
+		{
+			name:     "range and single column anchors",
+			marked:   "The {{adj:greatest}} enemy{{comma:,}}",
+			wantLine: "The greatest enemy,",
+			wantAnchors: Anchors{
+				"adj":   {Col: 4, ColEnd: 11},
+				"comma": {Col: 18},
+			},
+		},
+		{
+			name:     "empty anchor text",
+			marked:   "ab{{missing:}}c",
+			wantLine: "abc",
+			wantAnchors: Anchors{
+				"missing": {Col: 2},
+			},
+		},
+		{
+			name:     "wide characters before anchor",
+			marked:   "漢字 {{word:text}}",
+			wantLine: "漢字 text",
+			wantAnchors: Anchors{
+				"word": {Col: 5, ColEnd: 8},
+			},
+		},
+		{
+			name:    "marker is not closed",
+			marked:  "a {{name:text",
+			wantErr: &AnchorSyntaxError{},
+		},
+		{
+			name:    "marker without colon",
+			marked:  "a {{name}}",
+			wantErr: &AnchorSyntaxError{},
+		},
+		{
+			name:    "marker without name",
+			marked:  "a {{:text}}",
+			wantErr: &AnchorSyntaxError{},
+		},
+		{
+			name:    "name is defined twice",
+			marked:  "{{a:x}} {{a:y}}",
+			wantErr: &AnchorSyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLine, gotAnchors, err := ParseAnchors(tt.marked)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("ParseAnchors() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAnchors() unexpected error = %v", err)
+			}
+			if gotLine != tt.wantLine {
+				t.Errorf("ParseAnchors() gotLine = %v, want %v", gotLine, tt.wantLine)
+			}
+			if !reflect.DeepEqual(gotAnchors, tt.wantAnchors) {
+				t.Errorf("ParseAnchors() gotAnchors = %v, want %v", gotAnchors, tt.wantAnchors)
+			}
+		})
+	}
+}
+
+func TestAnchors_Annot(t *testing.T) {
+	anchors := Anchors{"adj": {Col: 4, ColEnd: 11}}
+
+	a, err := anchors.Annot("adj", "adjective")
+	if err != nil {
+		t.Fatalf("Annot() unexpected error = %v", err)
+	}
+	want := &Annot{Col: 4, ColEnd: 11, Lines: []string{"adjective"}}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("Annot() got = %v, want %v", a, want)
+	}
+
+	_, err = anchors.Annot("noun")
+	if !errors.Is(&UnknownAnchorError{}, err) {
+		t.Errorf("Annot() error = %v, wantErr %v", err, &UnknownAnchorError{})
+	}
+}
@@ -1,9 +1,11 @@
 package annot
 
 import (
+	"cmp"
 	"fmt"
 	"io"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/rivo/uniseg"
@@ -14,26 +16,90 @@ import (
 type Annot struct {
 	// Col is the position of the arrowhead of the annotation.
 	// E.g. 0 draws an arrow to the first character in a line.
-	Col int
+	Col int `json:"col"`
 
 	// ColEnd needs to be higher than Col. If ColEnd is set a
 	// range is annotated.
-	ColEnd int
+	ColEnd int `json:"colEnd,omitempty"`
 
 	// Lines is the text of the annotation represented in one or more lines.
-	Lines []string
-
+	Lines []string `json:"lines,omitempty"`
+
+	// MaxWidth is the maximum display width of a line in Lines. Longer
+	// lines are wrapped at line break opportunities of the Unicode line
+	// breaking algorithm, e.g. at spaces or between CJK characters. Words
+	// wider than MaxWidth are not broken. If MaxWidth is not set, lines
+	// are not wrapped.
+	MaxWidth int `json:"maxWidth,omitempty"`
+
+	// Bullet is put in front of every line in Lines, e.g. "• ".
+	// Wrapped lines are indented by the display width of Bullet.
+	Bullet string `json:"bullet,omitempty"`
+
+	// HangingIndent is the number of spaces wrapped lines are
+	// indented in addition to the Bullet.
+	HangingIndent int `json:"hangingIndent,omitempty"`
+
+	// Paragraphs separates the lines in Lines by a blank line.
+	Paragraphs bool `json:"paragraphs,omitempty"`
+
+	// Severity is the importance of the annotation, e.g. counted in the
+	// summary of WithSummary. WithSeverityStyles, WithSeverityArrowheads
+	// and WithSeverityStacking distinguish annotations by severity.
+	Severity Severity `json:"severity,omitempty"`
+
+	// Style is the style of the arrowhead or range, the stem, the
+	// connector and the lines of the annotation. It takes precedence over
+	// the styles of WithStyles and does not change the layout. A Style
+	// with other characters than digits and ";" is invalid.
+	Style Style `json:"style,omitempty"`
+
+	// SeeLine is the number of a related line, e.g. of the declaration
+	// of a name that is used in the annotated line. A reference
+	// "→ see line 42" is rendered below the lines. A SeeLine of 0 does
+	// not reference a line.
+	SeeLine int `json:"seeLine,omitempty"`
+
+	// Secondary draws the stem with a dashed pipe ┆ without an arrowhead
+	// and ranges with dashed lines like └┄┬┄┘, e.g. to distinguish notes
+	// and hints from primary findings.
+	Secondary bool `json:"secondary,omitempty"`
+
+	// Emphasis is the weight of the annotation. It does not change the
+	// layout.
+	Emphasis Emphasis `json:"emphasis,omitempty"`
+
+	// Line is the row of the annotated block of WriteDiagram or the line
+	// of the text of WriteDoc. It is counted from the origin like Col.
+	// Other functions ignore Line.
+	Line int `json:"line,omitempty"`
+
+	idx        int
+	num        int
+	col        int
+	colEnd     int
 	pipeColIdx int
 
-	row               int
-	lines             []*line
-	pipeLeadingSpaces []int
+	row   int
+	lines []*line
+
+	collapsed bool
+
+	// stemOnly draws a pipe instead of an arrowhead, e.g. for a stem
+	// that continues a stem drawn above.
+	stemOnly bool
+
+	measured   []measuredLine
+	measuredBy *Renderer
 }
 
 // line is an internal parallel to a string in Lines.
 type line struct {
-	length        int
-	leadingSpaces int
+	text   string
+	length int
+
+	// ref reports whether the line is the reference of SeeLine.
+	ref bool
 }
 
 type section int
@@ -63,185 +129,696 @@ const (
 	noAnnot
 )
 
-func (s *section) space() int {
+// space returns the needed space in front of the position returned
+// by colPosShift for a connector with a display width of connWidth and
+// the spacing sp.
+func (s *section) space(connWidth int, sp spacing) int {
 	switch *s {
 	case above, lineOne:
-		return 2
+		return sp.above
 	case lineTwo:
-		return 4
+		return connWidth + sp.lineTwo
 	case linesAfterSecond:
-		return 2
+		return sp.linesAfterSecond
 	case trailingSpaceLines:
-		return 1
+		return sp.trailingSpaceLines
 	default:
 		return -1
 	}
 }
 
-func (s *section) colPosShift() int {
+// colPosShift returns the shift of a position relative to the column
+// of a pipe for a connector with a display width of connWidth.
+func (s *section) colPosShift(connWidth int) int {
 	switch *s {
 	case above, lineOne:
 		return 0
 	case lineTwo, linesAfterSecond, trailingSpaceLines:
-		return 3
+		return connWidth
 	default:
 		return -1
 	}
 }
 
+// New returns an annotation of the column col with lines. It panics if
+// col is negative or higher than MaxCol.
+func New(col int, lines ...string) *Annot {
+	a := &Annot{Col: col, Lines: lines}
+	if col < 0 {
+		panic(newFieldRangeError(1, "Col", col, MaxCol))
+	}
+	if err := checkFields(1, a); err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// NewRange returns an annotation of the range from col to colEnd with
+// lines. It panics if col is negative, colEnd is not higher than col or a
+// column is higher than MaxCol.
+func NewRange(col, colEnd int, lines ...string) *Annot {
+	if colEnd <= col {
+		panic(newColExceedsColEndError(1, col, colEnd))
+	}
+	a := New(col, lines...)
+	a.ColEnd = colEnd
+	if err := checkFields(1, a); err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Shift moves the annotations delta columns, e.g. to annotate a substring
+// printed after a prefix. If a column of an annotation would be negative,
+// Shift returns a *ColOutOfRangeError and moves no annotation.
+func Shift(delta int, annots ...*Annot) error {
+	for aIdx, a := range annots {
+		if a == nil {
+			return newNilAnnotError(aIdx + 1)
+		}
+		if a.Col+delta < 0 {
+			return newColOutOfRangeError(aIdx+1, a.Col+delta, 0)
+		}
+		if a.Col+delta > MaxCol || a.ColEnd != 0 && a.ColEnd+delta > MaxCol {
+			return newFieldRangeError(aIdx+1, "Col", a.Col+delta, MaxCol)
+		}
+	}
+	for _, a := range annots {
+		a.Col += delta
+		if a.ColEnd != 0 {
+			a.ColEnd += delta
+		}
+	}
+	return nil
+}
+
 // AppendLines adds initial or appends additional lines to an annotation.
 func (a *Annot) AppendLines(lines ...string) {
 	a.Lines = append(a.Lines, lines...)
 }
 
+// WithRange sets Col and ColEnd and returns a for chaining, e.g.
+// New(0, "noun").WithRange(4, 11).WithMaxWidth(20). Like the other With
+// methods it does not validate, invalid annotations are reported when
+// they are rendered.
+func (a *Annot) WithRange(col, colEnd int) *Annot {
+	a.Col, a.ColEnd = col, colEnd
+	return a
+}
+
+// WithMaxWidth sets MaxWidth and returns a for chaining.
+func (a *Annot) WithMaxWidth(maxWidth int) *Annot {
+	a.MaxWidth = maxWidth
+	return a
+}
+
+// WithBullet sets Bullet and returns a for chaining.
+func (a *Annot) WithBullet(bullet string) *Annot {
+	a.Bullet = bullet
+	return a
+}
+
+// WithHangingIndent sets HangingIndent and returns a for chaining.
+func (a *Annot) WithHangingIndent(indent int) *Annot {
+	a.HangingIndent = indent
+	return a
+}
+
+// WithParagraphs sets Paragraphs and returns a for chaining.
+func (a *Annot) WithParagraphs() *Annot {
+	a.Paragraphs = true
+	return a
+}
+
+// WithSeverity sets Severity and returns a for chaining.
+func (a *Annot) WithSeverity(severity Severity) *Annot {
+	a.Severity = severity
+	return a
+}
+
+// WithStyle sets Style and returns a for chaining.
+func (a *Annot) WithStyle(style Style) *Annot {
+	a.Style = style
+	return a
+}
+
+// WithSeeLine sets SeeLine and returns a for chaining.
+func (a *Annot) WithSeeLine(line int) *Annot {
+	a.SeeLine = line
+	return a
+}
+
+// WithSecondary sets Secondary and returns a for chaining.
+func (a *Annot) WithSecondary() *Annot {
+	a.Secondary = true
+	return a
+}
+
+// WithEmphasis sets Emphasis and returns a for chaining.
+func (a *Annot) WithEmphasis(e Emphasis) *Annot {
+	a.Emphasis = e
+	return a
+}
+
+// WithLine sets Line and returns a for chaining.
+func (a *Annot) WithLine(line int) *Annot {
+	a.Line = line
+	return a
+}
+
 // String returns the rendered annotations as a string.
+//
+// Annotations are ordered by Col, then by ColEnd and then by the order they
+// are passed in. Of annotations with the same Col only the first one in
+// this order is rendered.
 func String(annots ...*Annot) string {
-	b := &strings.Builder{}
-	_ = Write(b, annots...)
-	return b.String()
+	return NewRenderer().String(annots...)
 }
 
 // Write renders the annotations and writes them to a writer w.
+// Annotations are ordered and removed like in String.
+//
+// Any values of the fields of Annot result in a rendering or a typed error,
+// never in a panic. Columns higher than MaxCol are invalid.
 func Write(w io.Writer, annots ...*Annot) error {
-	annots = slices.CompactFunc(annots, func(a1 *Annot, a2 *Annot) bool {
-		return a1.Col == a2.Col
-	})
+	return NewRenderer().Write(w, annots...)
+}
+
+// Write renders the annotations and writes them to a writer w.
+func (r *Renderer) Write(w io.Writer, annots ...*Annot) error {
+	l, err := r.Layout(annots...)
+	if l == nil {
+		return err
+	}
+	if writeErr := l.Write(w); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// MustString is like String but panics if the annotations are invalid.
+// It is intended for tests and examples where invalid annotations are
+// programmer mistakes.
+func MustString(annots ...*Annot) string {
+	return NewRenderer().MustString(annots...)
+}
+
+// MustWrite is like Write but panics if the annotations are invalid or
+// writing to w fails.
+func MustWrite(w io.Writer, annots ...*Annot) {
+	NewRenderer().MustWrite(w, annots...)
+}
+
+// AppendRender appends the rendered annotations to dst and returns the
+// extended buffer. Annotations are ordered and removed like in String.
+func AppendRender(dst []byte, annots ...*Annot) ([]byte, error) {
+	return NewRenderer().AppendRender(dst, annots...)
+}
+
+// AppendRender appends the rendered annotations to dst and returns the
+// extended buffer. It renders without intermediate strings, so callers
+// can reuse dst for every rendering. If the Renderer skips invalid
+// annotations, the valid annotations are appended and a *SkippedError
+// is returned.
+func (r *Renderer) AppendRender(dst []byte, annots ...*Annot) ([]byte, error) {
+	l, err := r.Layout(annots...)
+	if l == nil {
+		return dst, err
+	}
+	return l.AppendTo(dst), err
+}
+
+// MaxCol is the highest column of Col and ColEnd and the highest
+// HangingIndent. Higher values are invalid, so that untrusted annotations
+// cannot exhaust memory.
+const MaxCol = 1 << 20
+
+// arrange sorts the annotations stable by Col and ColEnd, removes
+// annotations with the same column and sets the stem columns of the
+// remaining annotations. Invalid annotations are returned as skipped if
+// the Renderer skips them.
+func (r *Renderer) arrange(annots []*Annot) ([]*Annot, []Skipped, error) {
+	checked := make([]*Annot, 0, len(annots))
+	var invalid []Skipped
+	for aIdx, a := range annots {
+		if err := checkFields(aIdx+1, a); err != nil {
+			if !r.skipInvalid {
+				return nil, nil, err
+			}
+			invalid = append(invalid, Skipped{Annot: a, Err: err})
+			continue
+		}
+		a.idx = aIdx
+		checked = append(checked, a)
+	}
+	annots = checked
 
 	if len(annots) == 0 {
-		return nil
+		return nil, invalid, nil
 	}
 
-	slices.SortFunc(annots, func(a *Annot, b *Annot) int {
-		return a.Col - b.Col
+	// Sort stable, so that annotations with the same Col and ColEnd
+	// keep the order they were passed in.
+	slices.SortStableFunc(annots, func(a *Annot, b *Annot) int {
+		if a.Col != b.Col {
+			return cmp.Compare(a.Col, b.Col)
+		}
+		if r.severityStacking && a.Severity != b.Severity {
+			return cmp.Compare(b.Severity, a.Severity)
+		}
+		return cmp.Compare(a.ColEnd, b.ColEnd)
 	})
 
+	if !r.strict {
+		annots = r.removeDuplicates(annots)
+	}
+
+	annots, skipped, err := r.validate(annots)
+	if err != nil {
+		return nil, nil, err
+	}
+	skipped = append(invalid, skipped...)
+
 	for aIdx, a := range annots {
+		a.num = aIdx + 1
+		a.col = a.Col - r.origin
+		a.colEnd = 0
 		if a.ColEnd != 0 {
-			if a.Col >= a.ColEnd {
-				return newColExceedsColEndError(aIdx+1, a.Col, a.ColEnd)
-			}
-			a.pipeColIdx = (a.Col + a.ColEnd) / 2
+			a.colEnd = a.ColEnd - r.origin
+			a.pipeColIdx = (a.col + a.colEnd) / 2
 		} else {
-			a.pipeColIdx = a.Col
+			a.pipeColIdx = a.col
 		}
-		if aIdx > 0 && annots[aIdx-1].ColEnd != 0 && annots[aIdx-1].ColEnd >= a.Col {
-			return newOverlapError(annots[aIdx-1].ColEnd, aIdx, a.Col)
+	}
+
+	return annots, skipped, nil
+}
+
+// removeDuplicates removes every annotation that is a duplicate of the
+// last kept annotation. Comparing with the kept annotation instead of the
+// preceding one prevents chains of near annotations from being removed.
+func (r *Renderer) removeDuplicates(annots []*Annot) []*Annot {
+	kept := annots[:1]
+	for _, a := range annots[1:] {
+		if !r.duplicates(kept[len(kept)-1], a) {
+			kept = append(kept, a)
 		}
-		a.createLines()
 	}
+	return kept
+}
 
-	// Start with second last annotation index and decrement.
-	// The last annotation will always be on row=0 and needs
-	// no adjustment.
-	for aIdxDecr := len(annots) - 2; 0 <= aIdxDecr; aIdxDecr-- {
-		setRow(annots[aIdxDecr], annots[aIdxDecr+1:])
+// checkFields returns an error if a is nil or a field of a has a value
+// that cannot be rendered regardless of other annotations. annotPos is the
+// position of a in the passed annotations starting at 1.
+func checkFields(annotPos int, a *Annot) error {
+	switch {
+	case a == nil:
+		return newNilAnnotError(annotPos)
+	case MaxCol < a.Col:
+		return newFieldRangeError(annotPos, "Col", a.Col, MaxCol)
+	case MaxCol < a.ColEnd:
+		return newFieldRangeError(annotPos, "ColEnd", a.ColEnd, MaxCol)
+	case a.HangingIndent < 0 || MaxCol < a.HangingIndent:
+		return newFieldRangeError(annotPos, "HangingIndent", a.HangingIndent, MaxCol)
+	case !a.Style.valid():
+		return newStyleError(annotPos, a.Style)
 	}
+	return nil
+}
 
-	return write(w, annots)
+// duplicates reports whether a2 is a duplicate of the preceding a1, i.e.
+// both have the same column or their stems are within the duplicate window.
+func (r *Renderer) duplicates(a1, a2 *Annot) bool {
+	if a1.Col == a2.Col {
+		return true
+	}
+	return r.dupWindow > 0 && abs(stemCol(a2)-stemCol(a1)) <= r.dupWindow
 }
 
-// createLines creates an array of lines parallel to Lines.
-func (a *Annot) createLines() {
-	if len(a.Lines) == 0 {
-		a.lines = make([]*line, 1)
-		a.lines[0] = &line{leadingSpaces: a.pipeColIdx}
-		return
+// stemCol returns the column of the stem of a.
+func stemCol(a *Annot) int {
+	if a.ColEnd != 0 {
+		return (a.Col + a.ColEnd) / 2
+	}
+	return a.Col
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
 	}
+	return i
+}
 
-	a.lines = make([]*line, len(a.Lines))
-	for i := range a.Lines {
-		leadingSpaces := a.pipeColIdx
-		if i > 0 {
-			leadingSpaces += 3
+// place creates the lines of the arranged annotations with rows limited to
+// width columns, sets the rows of the annotations and returns the rendered
+// rows. A width of 0 or less does not limit rows. Reserved columns are
+// part of width.
+func (r *Renderer) place(annots []*Annot, width int) ([][]Segment, error) {
+	width = r.available(width)
+	rows := r.placeRows(annots, width)
+	if r.gapMin > 0 && !r.linear {
+		rows = r.compressGaps(rows)
+	}
+	if r.overflow != overflowNone && width > 0 {
+		return r.clipRows(rows, width)
+	}
+	return rows, nil
+}
+
+// placeRows creates the lines of the arranged annotations with rows
+// limited to width columns, sets the rows of the annotations and returns
+// the rendered rows.
+func (r *Renderer) placeRows(annots []*Annot, width int) [][]Segment {
+	if r.linear {
+		return r.linearRows(annots)
+	}
+
+	if r.grid() {
+		for _, a := range annots {
+			r.createLines(a, wrapWidth(a, r.connWidth+1, r.gridCell))
+		}
+		return r.gridRows(annots)
+	}
+
+	if r.marginNotes() {
+		marginCol := r.marginColumn(annots)
+		for _, a := range annots {
+			r.createLines(a, wrapWidth(a, marginCol, width))
 		}
+		setMarginRows(annots)
+		return r.marginRows(annots)
+	}
 
-		a.lines[i] = &line{
-			length:        uniseg.StringWidth(a.Lines[i]),
-			leadingSpaces: leadingSpaces,
+	for aIdx, a := range annots {
+		a.row = 0
+		wrapAt := wrapWidth(a, a.pipeColIdx+r.connWidth, width)
+		if r.flowMin > 0 && aIdx+1 < len(annots) {
+			if flow := r.flowWidth(a, annots[aIdx+1]); flow >= r.flowMin && (wrapAt <= 0 || flow < wrapAt) {
+				wrapAt = flow
+			}
 		}
+		r.createLines(a, wrapAt)
 	}
+
+	r.setRows(annots)
+
+	return r.rows(annots)
 }
 
-func setRow(a *Annot, rightAnnots []*Annot) {
-	row := 0
+// validate returns the valid annotations of the sorted annotations. It
+// returns the error of the first invalid annotation or, if the Renderer
+// skips invalid annotations, all invalid annotations as skipped.
+func (r *Renderer) validate(annots []*Annot) ([]*Annot, []Skipped, error) {
+	valid := make([]*Annot, 0, len(annots))
+	var skipped []Skipped
 
-	for {
-		rowBefore := row - 1
-		if rowBefore != -1 {
-			setSpace(rowBefore, a, rightAnnots)
+	for aIdx, a := range annots {
+		var err error
+		var prev *Annot
+		if len(valid) > 0 {
+			prev = valid[len(valid)-1]
+		}
+		switch {
+		case a.Col < r.origin:
+			err = newColOutOfRangeError(aIdx+1, a.Col, r.origin)
+		case r.strict && prev != nil && r.duplicates(prev, a):
+			err = newDuplicateColError(aIdx, prev.Col, aIdx+1, a.Col)
+		case a.ColEnd != 0 && a.Col >= a.ColEnd:
+			err = newColExceedsColEndError(aIdx+1, a.Col, a.ColEnd)
+		case prev != nil && prev.ColEnd != 0 && prev.ColEnd >= a.Col:
+			err = newOverlapError(aIdx, prev, a)
 		}
 
-		annotFits := checkLinesAndSetSpaces(row, a, rightAnnots)
-		if annotFits {
-			return
+		if err == nil {
+			valid = append(valid, a)
+			continue
 		}
-		row++
+		if !r.skipInvalid {
+			return nil, nil, err
+		}
+		skipped = append(skipped, Skipped{Annot: a, Err: err})
 	}
+	return valid, skipped, nil
 }
 
-func setSpace(rowBefore int, a *Annot, rightAnnots []*Annot) {
-	closestA, s := closestAnnot(rowBefore, rightAnnots, 0)
-	switch s {
-	case above:
-		closestA.pipeLeadingSpaces[rowBefore] = closestA.pipeColIdx + s.colPosShift() - a.pipeColIdx - 1
-	case lineOne, lineTwo, linesAfterSecond:
-		closestA.lines[rowBefore-closestA.row].leadingSpaces = closestA.pipeColIdx + s.colPosShift() - a.pipeColIdx - 1
-	case noAnnot, trailingSpaceLines:
-		// Do nothing
+// RequiredWidth returns the display width of the widest row of the
+// rendered annotations. It returns 0 if the annotations cannot be rendered.
+func RequiredWidth(annots ...*Annot) int {
+	return NewRenderer().RequiredWidth(annots...)
+}
+
+// RequiredWidth returns the display width of the widest row of the
+// rendered annotations. It returns 0 if the annotations cannot be rendered.
+func (r *Renderer) RequiredWidth(annots ...*Annot) int {
+	l, _ := r.Layout(annots...)
+	if l == nil {
+		return 0
 	}
+	return l.requiredWidth()
 }
 
-func checkLinesAndSetSpaces(row int, a *Annot, rightAnnots []*Annot) bool {
-	for aLineIdx := 0; aLineIdx < len(a.lines); aLineIdx++ {
-		lineFits := checkLineAndSetSpace(row, aLineIdx, a, rightAnnots)
-		if !lineFits {
-			return false
+// createLines creates an array of lines parallel to Lines. The lines are
+// wrapped at wrapWidth and formatted with Bullet, HangingIndent and
+// Paragraphs. A wrapWidth of 0 or less does not wrap lines.
+func (r *Renderer) createLines(a *Annot, wrapWidth int) {
+	if len(a.Lines) == 0 {
+		number := r.numberLabel(a)
+		a.lines = []*line{{text: number, length: r.stringWidth(number)}}
+		a.lines = r.appendSeeLine(a.lines, a)
+		return
+	}
+
+	// A number is put in front of the first line and all other lines are
+	// indented by its width.
+	number := r.numberLabel(a)
+	numberWidth := 0
+	if number != "" {
+		number += " "
+		numberWidth = r.stringWidth(number)
+	}
+	numberIndent := strings.Repeat(" ", numberWidth)
+
+	bulletWidth := numberWidth + r.stringWidth(a.Bullet)
+	indentWidth := bulletWidth + a.HangingIndent
+	indent := strings.Repeat(" ", indentWidth)
+
+	a.lines = make([]*line, 0, len(a.Lines))
+	for i, m := range r.measure(a) {
+		if i > 0 && a.Paragraphs {
+			a.lines = append(a.lines, &line{})
+		}
+
+		wrapped := []*line{{text: m.text, length: m.width}}
+		if wrapWidth > 0 {
+			segments := m.segments
+			if r.overflow == OverflowBreak {
+				segments = r.breakSegments(segments, max(wrapWidth-indentWidth, 1))
+			}
+			wrapped = wrap(segments, wrapWidth-bulletWidth, wrapWidth-indentWidth)
+		}
+		for j, l := range wrapped {
+			switch {
+			case i == 0 && j == 0:
+				l.text = number + a.Bullet + l.text
+				l.length += bulletWidth
+			case j == 0:
+				l.text = numberIndent + a.Bullet + l.text
+				l.length += bulletWidth
+			default:
+				l.text = indent + l.text
+				l.length += indentWidth
+			}
+			a.lines = append(a.lines, l)
 		}
 	}
-	return true
+
+	a.collapsed = r.collapse > 0 && len(a.lines) > r.collapse
+	if a.collapsed {
+		a.lines = append(a.lines[:r.collapse], r.collapseTail(len(a.lines)-r.collapse))
+	}
+	a.lines = r.appendSeeLine(a.lines, a)
 }
 
-func checkLineAndSetSpace(row, aLineIdx int, a *Annot, rightAnnots []*Annot) bool {
-	rowPlusLineIdx := row + aLineIdx
+// appendSeeLine appends the line of the reference of SeeLine to lines if
+// a references a line.
+func (r *Renderer) appendSeeLine(lines []*line, a *Annot) []*line {
+	if a.SeeLine == 0 {
+		return lines
+	}
+	text := r.seeLine(a.SeeLine)
+	return append(lines, &line{text: text, length: r.stringWidth(text), ref: true})
+}
 
-	closestA, s := closestAnnot(rowPlusLineIdx, rightAnnots, 1)
-	if s == noAnnot {
-		return true
+// seeLine returns the reference of a line, e.g. "→ see line 42".
+func (r *Renderer) seeLine(line int) string {
+	return r.glyphs.Reference + " see line " + strconv.Itoa(line)
+}
+
+// collapseTail returns the line that replaces hidden lines of a collapsed
+// annotation.
+func (r *Renderer) collapseTail(hidden int) *line {
+	text := fmt.Sprintf("%s (+%d more lines)", r.glyphs.Ellipsis, hidden)
+	if hidden == 1 {
+		text = r.glyphs.Ellipsis + " (+1 more line)"
+	}
+	return &line{text: text, length: r.stringWidth(text)}
+}
+
+// wrapWidth returns the width lines of an annotation are wrapped at, if
+// they start at column textCol and rows are limited to width columns.
+func wrapWidth(a *Annot, textCol, width int) int {
+	if width <= 0 {
+		return a.MaxWidth
+	}
+	available := max(width-textCol, 1)
+	if a.MaxWidth <= 0 {
+		return available
 	}
+	return min(a.MaxWidth, available)
+}
 
-	//            3 for "└─ " or "   " (indentation)
-	lineLength := 3 + a.lines[aLineIdx].length
+// flowWidth returns the width lines of an annotation a can be wrapped at,
+// so that they fit left of the stem of the next annotation in every row.
+func (r *Renderer) flowWidth(a, next *Annot) int {
+	sp := r.spacing()
+	space := max(sp.above, sp.lineTwo, sp.linesAfterSecond-r.connWidth, sp.trailingSpaceLines-r.connWidth)
+	return next.pipeColIdx - a.pipeColIdx - r.connWidth - space
+}
 
-	remainingSpaces := closestA.pipeColIdx + s.colPosShift() - a.pipeColIdx - lineLength
+// measuredLine is a line in Lines split into measured line segments.
+type measuredLine struct {
+	text     string
+	width    int
+	segments []lineSegment
+}
+
+// lineSegment is a part of a line that ends at a line break opportunity.
+type lineSegment struct {
+	text         string
+	width        int
+	trimmedWidth int
+	mustBreak    bool
+}
 
-	if remainingSpaces-s.space() < 0 {
-		a.row++
-		a.pipeLeadingSpaces = append(a.pipeLeadingSpaces, a.pipeColIdx)
-		return false
+// measure returns the measured lines of Lines. Lines that were measured
+// by the same Renderer before are not measured again.
+func (r *Renderer) measure(a *Annot) []measuredLine {
+	if a.measuredBy != r || len(a.measured) != len(a.Lines) {
+		a.measured = make([]measuredLine, len(a.Lines))
 	}
+	for i, l := range a.Lines {
+		if a.measuredBy == r && a.measured[i].text == l {
+			continue
+		}
+		segments := r.segments(l)
+		width := 0
+		for _, seg := range segments {
+			width += seg.width
+		}
+		a.measured[i] = measuredLine{text: l, width: width, segments: segments}
+	}
+	a.measuredBy = r
+	return a.measured
+}
 
-	switch s {
-	case above:
-		closestA.pipeLeadingSpaces[rowPlusLineIdx] = remainingSpaces
-	case lineOne, lineTwo, linesAfterSecond:
-		closestA.lines[rowPlusLineIdx-closestA.row].leadingSpaces = remainingSpaces
-	case trailingSpaceLines:
-		closestA2, s2 := closestAnnot(rowPlusLineIdx, rightAnnots, 0)
-		if s2 == noAnnot {
-			return true
+// segments splits s at line break opportunities. Line break opportunities
+// are determined by the Unicode line breaking algorithm, so scripts without
+// spaces are split as well.
+func (r *Renderer) segments(s string) []lineSegment {
+	var segments []lineSegment
+	state := -1
+	for s != "" {
+		var segment string
+		var mustBreak bool
+		segment, s, mustBreak, state = uniseg.FirstLineSegmentInString(s, state)
+		segments = append(segments, lineSegment{
+			text:         segment,
+			width:        r.stringWidth(segment),
+			trimmedWidth: r.stringWidth(strings.TrimRight(segment, " \n\r")),
+			mustBreak:    mustBreak && s != "",
+		})
+	}
+	return segments
+}
+
+// wrap joins segments to lines with a display width of at most firstWidth
+// for the first line and restWidth for all other lines. Segments wider than
+// the width get a line of their own.
+func wrap(segments []lineSegment, firstWidth, restWidth int) []*line {
+	width := firstWidth
+
+	var lines []*line
+	current := &strings.Builder{}
+	currentWidth := 0
+	currentTrimmedWidth := 0
+	flush := func() {
+		lines = append(lines, &line{
+			text:   strings.TrimRight(current.String(), " \n\r"),
+			length: currentTrimmedWidth,
+		})
+		current.Reset()
+		currentWidth = 0
+		currentTrimmedWidth = 0
+		width = restWidth
+	}
+
+	for _, seg := range segments {
+		if current.Len() != 0 && width < currentWidth+seg.trimmedWidth {
+			flush()
 		}
-		leadingSpaces2 := closestA2.pipeColIdx + s2.colPosShift() - a.pipeColIdx - lineLength
-		if s2 == above {
-			closestA2.pipeLeadingSpaces[rowPlusLineIdx] = leadingSpaces2
-			return true
+		current.WriteString(seg.text)
+		currentTrimmedWidth = currentWidth + seg.trimmedWidth
+		currentWidth += seg.width
+
+		if seg.mustBreak {
+			flush()
+		}
+	}
+	flush()
+	return lines
+}
+
+func (r *Renderer) setRow(a *Annot, rightAnnots []*Annot) {
+	row := 0
+	for !r.linesFit(row, a, rightAnnots, r.spacing()) {
+		if r.severityStacking && outranks(a, rightAnnots) && r.linesFit(row, a, rightAnnots, tightSpacing()) {
+			break
+		}
+		if r.pushHook != nil {
+			tight := r.linesFit(row, a, rightAnnots, tightSpacing())
+			if !r.pushHook(Push{Annot: a, Row: row, Tight: tight}) && tight {
+				break
+			}
+		}
+		row++
+	}
+	a.row = row
+}
+
+func (r *Renderer) linesFit(row int, a *Annot, rightAnnots []*Annot, sp spacing) bool {
+	for aLineIdx := 0; aLineIdx < len(a.lines); aLineIdx++ {
+		if !r.lineFits(row, aLineIdx, a, rightAnnots, sp) {
+			return false
 		}
-		closestA2.lines[rowPlusLineIdx-closestA2.row].leadingSpaces = leadingSpaces2
-	default:
-		panic("should never be reached")
 	}
 	return true
 }
 
+func (r *Renderer) lineFits(row, aLineIdx int, a *Annot, rightAnnots []*Annot, sp spacing) bool {
+	closestA, s := closestAnnot(row+aLineIdx, rightAnnots, 1)
+	if s == noAnnot {
+		return true
+	}
+
+	// Connector "└─ " or indentation "   " in front of the line.
+	lineLength := r.connWidth + a.lines[aLineIdx].length
+
+	remainingSpaces := closestA.pipeColIdx + s.colPosShift(r.connWidth) - a.pipeColIdx - lineLength
+
+	return remainingSpaces-s.space(r.connWidth, sp) >= 0
+}
+
 func closestAnnot(row int, rightAnnots []*Annot, trailingVerticalSpaceLinesCount int) (*Annot, section) {
 	for _, a := range rightAnnots {
 		if row < a.row {
@@ -264,75 +841,95 @@ func closestAnnot(row int, rightAnnots []*Annot, trailingVerticalSpaceLinesCount
 	return nil, noAnnot
 }
 
-func write(writer io.Writer, annots []*Annot) error {
+// rows returns the rendered rows of the laid out annotations.
+func (r *Renderer) rows(annots []*Annot) [][]Segment {
 	rowCount := 0
 	for _, a := range annots {
 		rowCount = max(rowCount, a.row+len(a.lines))
 	}
 
-	b := &strings.Builder{}
-
-	b.WriteString(arrowOrRangeString(annots))
-
-	b.WriteString("\n")
-	_, err := fmt.Fprint(writer, b.String())
-	if err != nil {
-		return err
-	}
-	b.Reset()
+	rows := make([][]Segment, rowCount+1)
+	rows[0] = r.markerRow(annots)
 
 	for row := 0; row < rowCount; row++ {
+		var segments []Segment
 		for _, a := range annots {
 			switch {
 			case row < a.row:
-				b.WriteString(strings.Repeat(" ", a.pipeLeadingSpaces[row]))
-				b.WriteString("│")
+				segments = append(segments, Segment{Col: a.pipeColIdx, Text: r.pipe(a), Kind: PipeSegment, Annot: a, Index: a.idx})
 			case row == a.row:
-				b.WriteString(strings.Repeat(" ", a.lines[row-a.row].leadingSpaces))
-				b.WriteString("└─ ")
-				if len(a.Lines) != 0 {
-					b.WriteString(a.Lines[0])
-				}
+				segments = append(segments, Segment{Col: a.pipeColIdx, Text: r.connector, Kind: ConnectorSegment, Annot: a, Index: a.idx})
+				segments = appendLine(segments, a.pipeColIdx+r.connWidth, a.lines[0], a)
 			case row < a.row+len(a.lines):
-				b.WriteString(strings.Repeat(" ", a.lines[row-a.row].leadingSpaces))
-				b.WriteString(a.Lines[row-a.row])
+				segments = appendLine(segments, a.pipeColIdx+r.connWidth, a.lines[row-a.row], a)
 			}
 		}
-		b.WriteString("\n")
-		_, err = fmt.Fprint(writer, b.String())
-		if err != nil {
-			return err
-		}
-		b.Reset()
+		rows[row+1] = segments
 	}
 
-	return nil
+	return rows
 }
 
-func arrowOrRangeString(annots []*Annot) string {
-	widthWritten := 0
+// appendLine appends a segment of the line l if it is not empty.
+func appendLine(segments []Segment, col int, l *line, a *Annot) []Segment {
+	if l.ref {
+		return append(segments, Segment{Col: col, Text: l.text, Kind: RefSegment, Annot: a, Index: a.idx})
+	}
+	return appendText(segments, col, l.text, a)
+}
 
-	b := &strings.Builder{}
+// appendText appends a text segment if text is not empty.
+func appendText(segments []Segment, col int, text string, a *Annot) []Segment {
+	if text == "" {
+		return segments
+	}
+	return append(segments, Segment{Col: col, Text: text, Kind: TextSegment, Annot: a, Index: a.idx})
+}
 
-	for _, a := range annots {
-		if a.ColEnd == 0 {
-			b.WriteString(strings.Repeat(" ", a.pipeColIdx-widthWritten))
-			b.WriteString("↑")
-			widthWritten = a.pipeColIdx + 1
+// markerRow returns the first row with arrowheads and ranges.
+func (r *Renderer) markerRow(annots []*Annot) []Segment {
+	segments := make([]Segment, len(annots))
+
+	for aIdx, a := range annots {
+		if a.colEnd == 0 {
+			arrowhead := r.arrowhead(a)
+			switch {
+			case a.stemOnly:
+				arrowhead = r.pipe(a)
+			case r.numberLabel(a) != "":
+				arrowhead = r.numberLabel(a)
+			case r.noArrowheads || a.Secondary:
+				arrowhead = r.pipe(a)
+			}
+			segments[aIdx] = Segment{Col: a.pipeColIdx, Text: arrowhead, Kind: ArrowSegment, Annot: a, Index: a.idx}
 			continue
 		}
 
-		b.WriteString(strings.Repeat(" ", a.Col-widthWritten))
-		if a.Col == a.pipeColIdx {
-			b.WriteString("├")
-		} else {
-			b.WriteString("└")
-			b.WriteString(strings.Repeat("─", a.pipeColIdx-a.Col-1))
-			b.WriteString("┬")
+		segments[aIdx] = Segment{Col: a.col, Text: r.rangeText(a.col, a.colEnd, a.pipeColIdx, r.numberLabel(a), r.horizontal(a)), Kind: RangeSegment, Annot: a, Index: a.idx}
+	}
+	return segments
+}
+
+// rangeText returns the drawn range from col to colEnd with a stem in
+// pipeCol, e.g. "└─┬─┘", drawn with the horizontal line horizontal. A
+// number replaces the stem glyph if it is not empty.
+func (r *Renderer) rangeText(col, colEnd, pipeCol int, number, horizontal string) string {
+	b := &strings.Builder{}
+	switch {
+	case number != "":
+		if col != pipeCol {
+			b.WriteString(r.glyphs.RangeLeft)
+			b.WriteString(strings.Repeat(horizontal, pipeCol-col-1))
 		}
-		b.WriteString(strings.Repeat("─", a.ColEnd-a.pipeColIdx-1))
-		b.WriteString("┘")
-		widthWritten = a.ColEnd + 1
+		b.WriteString(number)
+	case col == pipeCol:
+		b.WriteString(r.glyphs.Tee)
+	default:
+		b.WriteString(r.glyphs.RangeLeft)
+		b.WriteString(strings.Repeat(horizontal, pipeCol-col-1))
+		b.WriteString(r.glyphs.RangeMid)
 	}
+	b.WriteString(strings.Repeat(horizontal, colEnd-pipeCol-1))
+	b.WriteString(r.glyphs.RangeRight)
 	return b.String()
 }
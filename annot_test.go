@@ -3,6 +3,8 @@ package annot
 import (
 	"bytes"
 	"errors"
+	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -372,6 +374,72 @@ func TestWrite(t *testing.T) {
  │  │└─ 
  │  └─ 
  └─ 
+`,
+		},
+		{
+			name: "wrap lines at max width",
+			annots: []*Annot{
+				{Col: 2, MaxWidth: 4, Lines: []string{"a longword b", "c d e"}},
+			},
+			wantW: `
+  ↑
+  └─ a
+     longword
+     b
+     c d
+     e
+`,
+		},
+		{
+			name: "wrapped annotation shares row with annotation without max width",
+			annots: []*Annot{
+				{Col: 0, MaxWidth: 10, Lines: []string{"a verbose annotation that wraps narrow"}},
+				{Col: 16, Lines: []string{"short", "text"}},
+			},
+			wantW: `
+↑               ↑
+└─ a verbose    └─ short
+   annotation      text
+   that wraps
+   narrow
+`,
+		},
+		{
+			name: "bullets with hanging indent of wrapped lines",
+			annots: []*Annot{
+				{Col: 0, MaxWidth: 14, Bullet: "• ", Lines: []string{"first point that wraps", "second"}},
+			},
+			wantW: `
+↑
+└─ • first point
+     that wraps
+   • second
+`,
+		},
+		{
+			name: "hanging indent and paragraphs",
+			annots: []*Annot{
+				{Col: 0, MaxWidth: 12, HangingIndent: 2, Paragraphs: true, Lines: []string{"paragraph one wraps", "two"}},
+			},
+			wantW: `
+↑
+└─ paragraph
+     one wraps
+
+   two
+`,
+		},
+		{
+			name: "wrap japanese text without spaces",
+			annots: []*Annot{
+				{Col: 0, MaxWidth: 8, Lines: []string{"漢字仮名交じり文を折り返す"}},
+			},
+			wantW: `
+↑
+└─ 漢字仮名
+   交じり文
+   を折り返
+   す
 `,
 		},
 		{
@@ -404,6 +472,29 @@ func TestWrite(t *testing.T) {
 			},
 			wantErr: &ColExceedsColEndError{},
 		},
+		{
+			name: "remove not adjacent annotation with same column position",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 10, Lines: []string{"line1"}},
+				{Col: 0, Lines: []string{"same column position"}},
+			},
+			wantW: `
+↑         ↑
+└─ line1  └─ line1
+`,
+		},
+		{
+			name: "keep arrow instead of range with same column position",
+			annots: []*Annot{
+				{Col: 0, ColEnd: 2, Lines: []string{"range"}},
+				{Col: 0, Lines: []string{"arrow"}},
+			},
+			wantW: `
+↑
+└─ arrow
+`,
+		},
 		{
 			name: "remove second annotation with same column position",
 			annots: []*Annot{
@@ -413,6 +504,37 @@ func TestWrite(t *testing.T) {
 			wantW: `
 ↑
 └─ line1
+`,
+		},
+		{
+			name: "secondary annotation",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"primary"}},
+				{Col: 4, ColEnd: 8, Secondary: true, Lines: []string{"secondary"}},
+				{Col: 10, Secondary: true, Lines: []string{"secondary"}},
+			},
+			wantW: `
+↑   └┄┬┄┘ ┆
+│     ┆   └─ secondary
+│     ┆
+│     └─ secondary
+│
+└─ primary
+`,
+		},
+		{
+			name: "reference to other line",
+			annots: []*Annot{
+				{Col: 0, SeeLine: 3, Lines: []string{"used here"}},
+				{Col: 4, SeeLine: 42},
+			},
+			wantW: `
+↑   ↑
+│   └─ 
+│      → see line 42
+│
+└─ used here
+   → see line 3
 `,
 		},
 	}
@@ -432,3 +554,307 @@ func TestWrite(t *testing.T) {
 		})
 	}
 }
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		firstWidth int
+		restWidth  int
+		want       []string
+	}{
+		{
+			name:       "fits",
+			s:          "a b",
+			firstWidth: 3,
+			restWidth:  3,
+			want:       []string{"a b"},
+		},
+		{
+			name:       "break at spaces",
+			s:          "a longword b",
+			firstWidth: 4,
+			restWidth:  4,
+			want:       []string{"a", "longword", "b"},
+		},
+		{
+			name:       "break after hyphen",
+			s:          "well-known",
+			firstWidth: 6,
+			restWidth:  6,
+			want:       []string{"well-", "known"},
+		},
+		{
+			name:       "break between CJK characters",
+			s:          "漢字仮名",
+			firstWidth: 4,
+			restWidth:  4,
+			want:       []string{"漢字", "仮名"},
+		},
+		{
+			name:       "different width of first line",
+			s:          "aa bb cc dd",
+			firstWidth: 2,
+			restWidth:  5,
+			want:       []string{"aa", "bb cc", "dd"},
+		},
+		{
+			name:       "empty string",
+			s:          "",
+			firstWidth: 1,
+			restWidth:  1,
+			want:       []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineTexts(wrap(NewRenderer().segments(tt.s), tt.firstWidth, tt.restWidth)); !slices.Equal(got, tt.want) {
+				t.Errorf("wrap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredWidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		annots []*Annot
+		want   int
+	}{
+		{
+			name: "no annotations",
+			want: 0,
+		},
+		{
+			name:   "empty annotation",
+			annots: []*Annot{{Col: 2}},
+			want:   5,
+		},
+		{
+			name: "longest line of a lower annotation",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1", "line2verylong"}},
+				{Col: 6, Lines: []string{"line1"}},
+			},
+			want: 16,
+		},
+		{
+			name:   "range wider than line",
+			annots: []*Annot{{Col: 0, ColEnd: 10, Lines: []string{"a"}}},
+			want:   11,
+		},
+		{
+			name:   "wide characters",
+			annots: []*Annot{{Col: 0, Lines: []string{"漢字"}}},
+			want:   7,
+		},
+		{
+			name:   "invalid annotation",
+			annots: []*Annot{{Col: 1, ColEnd: 1}},
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequiredWidth(tt.annots...); got != tt.want {
+				t.Errorf("RequiredWidth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite_sameAnnotsTwice(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Lines: []string{"line1"}},
+		{Col: 2, Lines: []string{"line1"}},
+	}
+	first := String(annots...)
+	if second := String(annots...); second != first {
+		t.Errorf("String() second = %v, want %v", second, first)
+	}
+}
+
+func TestAppendRender(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Lines: []string{"line1"}},
+		{Col: 2, ColEnd: 4, Lines: []string{"line1", "line2"}},
+	}
+
+	dst := []byte("prefix\n")
+	got, err := AppendRender(dst, annots...)
+	if err != nil {
+		t.Fatalf("AppendRender() unexpected error = %v", err)
+	}
+	if want := "prefix\n" + String(annots...); string(got) != want {
+		t.Errorf("AppendRender() got = %v, want %v", string(got), want)
+	}
+
+	got, err = AppendRender(got[:0], &Annot{Col: 1, ColEnd: 1})
+	if !errors.Is(err, &ColExceedsColEndError{}) || len(got) != 0 {
+		t.Errorf("AppendRender() got = %q, error = %v, want empty and %T", got, err, &ColExceedsColEndError{})
+	}
+}
+
+func TestMustString(t *testing.T) {
+	annots := []*Annot{{Col: 1, Lines: []string{"line1"}}}
+	if got, want := MustString(annots...), String(annots...); got != want {
+		t.Errorf("MustString() = %v, want %v", got, want)
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, &ColExceedsColEndError{}) {
+			t.Errorf("MustString() recovered = %v, want %T", err, &ColExceedsColEndError{})
+		}
+	}()
+	MustString(&Annot{Col: 1, ColEnd: 1})
+	t.Errorf("MustString() did not panic")
+}
+
+func BenchmarkAppendRender(b *testing.B) {
+	annots := []*Annot{
+		{Col: 1, Lines: []string{"article"}},
+		{Col: 4, ColEnd: 11, Lines: []string{"adjective"}},
+		{Col: 22, ColEnd: 30, Lines: []string{"noun", "of the sentence"}},
+		{Col: 48, Lines: []string{"comma"}},
+	}
+	r := NewRenderer()
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		buf, err = r.AppendRender(buf[:0], annots...)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func lineTexts(lines []*line) []string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.text
+	}
+	return texts
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		new     func() *Annot
+		want    *Annot
+		wantErr error
+	}{
+		{
+			name: "arrow",
+			new:  func() *Annot { return New(3, "line1", "line2") },
+			want: &Annot{Col: 3, Lines: []string{"line1", "line2"}},
+		},
+		{
+			name: "range",
+			new:  func() *Annot { return NewRange(3, 5, "line1") },
+			want: &Annot{Col: 3, ColEnd: 5, Lines: []string{"line1"}},
+		},
+		{
+			name:    "negative column",
+			new:     func() *Annot { return New(-1) },
+			wantErr: &FieldRangeError{},
+		},
+		{
+			name:    "column higher than MaxCol",
+			new:     func() *Annot { return NewRange(0, MaxCol+1) },
+			wantErr: &FieldRangeError{},
+		},
+		{
+			name:    "col end equals col",
+			new:     func() *Annot { return NewRange(3, 3) },
+			wantErr: &ColExceedsColEndError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+					t.Errorf("New() recovered = %v, wantErr %v", err, tt.wantErr)
+				}
+			}()
+			got := tt.new()
+			if got.Col != tt.want.Col || got.ColEnd != tt.want.ColEnd || !slices.Equal(got.Lines, tt.want.Lines) {
+				t.Errorf("New() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnot_With(t *testing.T) {
+	got := New(0, "wrapped text").
+		WithRange(2, 5).
+		WithMaxWidth(7).
+		WithBullet("• ").
+		WithHangingIndent(1).
+		WithParagraphs().
+		WithSecondary().
+		WithLine(3)
+	want := &Annot{
+		Col: 2, ColEnd: 5, Lines: []string{"wrapped text"},
+		MaxWidth: 7, Bullet: "• ", HangingIndent: 1, Paragraphs: true, Secondary: true, Line: 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("With() = %+v, want %+v", got, want)
+	}
+}
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		name    string
+		delta   int
+		annots  []*Annot
+		want    []*Annot
+		wantErr error
+	}{
+		{
+			name:   "right",
+			delta:  3,
+			annots: []*Annot{{Col: 0}, {Col: 2, ColEnd: 4}},
+			want:   []*Annot{{Col: 3}, {Col: 5, ColEnd: 7}},
+		},
+		{
+			name:   "left",
+			delta:  -2,
+			annots: []*Annot{{Col: 2}, {Col: 4, ColEnd: 6}},
+			want:   []*Annot{{Col: 0}, {Col: 2, ColEnd: 4}},
+		},
+		{
+			name:    "negative column",
+			delta:   -3,
+			annots:  []*Annot{{Col: 4}, {Col: 2}},
+			want:    []*Annot{{Col: 4}, {Col: 2}},
+			wantErr: &ColOutOfRangeError{},
+		},
+		{
+			name:    "column higher than MaxCol",
+			delta:   MaxCol,
+			annots:  []*Annot{{Col: 0, ColEnd: 1}},
+			want:    []*Annot{{Col: 0, ColEnd: 1}},
+			wantErr: &FieldRangeError{},
+		},
+		{
+			name:    "nil annotation",
+			annots:  []*Annot{nil},
+			want:    []*Annot{nil},
+			wantErr: &NilAnnotError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Shift(tt.delta, tt.annots...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("Shift() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.annots, tt.want) {
+				t.Errorf("Shift() annots = %v, want %v", tt.annots, tt.want)
+			}
+		})
+	}
+}
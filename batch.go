@@ -0,0 +1,96 @@
+package annot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Entry is a source line with its line number and annotations.
+type Entry struct {
+	// LineNum is the number shown in the gutter. A LineNum of 0
+	// leaves the gutter of the entry blank.
+	LineNum int
+
+	// Line is the annotated source line.
+	Line string
+
+	// Annots are the annotations of Line.
+	Annots []*Annot
+}
+
+// WriteBatch renders all entries with a line number gutter and writes them
+// to a writer w. The gutters of all entries have the same width and entries
+// are separated by a blank line.
+func WriteBatch(w io.Writer, entries []Entry) error {
+	return NewRenderer().WriteBatch(w, entries)
+}
+
+// WriteBatch renders all entries with a line number gutter and writes them
+// to a writer w. The gutters of all entries have the same width and entries
+// are separated by a blank line.
+func (r *Renderer) WriteBatch(w io.Writer, entries []Entry) error {
+	r = r.coloredFor(w)
+	gutterWidth := 0
+	for _, e := range entries {
+		gutterWidth = max(gutterWidth, lineNumWidth(e.LineNum))
+	}
+
+	b := &strings.Builder{}
+	for eIdx, e := range entries {
+		if eIdx > 0 {
+			b.WriteString("\n")
+		}
+		if err := r.writeEntry(b, gutterWidth, e); err != nil {
+			return err
+		}
+
+		_, err := fmt.Fprint(w, b.String())
+		if err != nil {
+			return err
+		}
+		b.Reset()
+	}
+	return nil
+}
+
+// writeEntry writes the line and the rendered annotations of an entry
+// with a gutter of gutterWidth to b.
+func (r *Renderer) writeEntry(b *strings.Builder, gutterWidth int, e Entry) error {
+	num := ""
+	if e.LineNum != 0 {
+		num = strconv.Itoa(e.LineNum)
+	}
+	r.writeGutter(b, gutterWidth, num)
+	b.WriteString(e.Line)
+	b.WriteString("\n")
+
+	annotsBuf := &bytes.Buffer{}
+	if err := r.Write(annotsBuf, e.Annots...); err != nil {
+		return err
+	}
+	for _, row := range strings.SplitAfter(annotsBuf.String(), "\n") {
+		if row == "" {
+			continue
+		}
+		r.writeGutter(b, gutterWidth, "")
+		b.WriteString(row)
+	}
+	return nil
+}
+
+// lineNumWidth returns the width of a line number in a gutter.
+func lineNumWidth(lineNum int) int {
+	if lineNum == 0 {
+		return 0
+	}
+	return len(strconv.Itoa(lineNum))
+}
+
+func (r *Renderer) writeGutter(b *strings.Builder, width int, num string) {
+	b.WriteString(strings.Repeat(" ", width-len(num)))
+	b.WriteString(num)
+	b.WriteString(" " + r.glyphs.Gutter + " ")
+}
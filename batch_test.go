@@ -0,0 +1,75 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		wantW   string
+		wantErr error
+	}{
+		{
+			name:    "no entries",
+			entries: nil,
+			wantW: `
+`,
+		},
+		{
+			name: "one entry",
+			entries: []Entry{
+				{LineNum: 7, Line: "x := 1", Annots: []*Annot{{Col: 0, Lines: []string{"unused"}}}},
+			},
+			wantW: `
+7 │ x := 1
+  │ ↑
+  │ └─ unused
+`,
+		},
+		{
+			name: "aligned gutters",
+			entries: []Entry{
+				{LineNum: 9, Line: "a b", Annots: []*Annot{{Col: 2, Lines: []string{"b"}}}},
+				{LineNum: 120, Line: "c d", Annots: []*Annot{{Col: 0, ColEnd: 2, Lines: []string{"c d"}}}},
+				{Line: "no line number"},
+			},
+			wantW: `
+  9 │ a b
+    │   ↑
+    │   └─ b
+
+120 │ c d
+    │ └┬┘
+    │  └─ c d
+
+    │ no line number
+`,
+		},
+		{
+			name: "annotation error",
+			entries: []Entry{
+				{LineNum: 1, Line: "a", Annots: []*Annot{{Col: 1, ColEnd: 0}, {Col: 0, ColEnd: 1}}},
+			},
+			wantErr: &OverlapError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := WriteBatch(w, tt.entries)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("WriteBatch() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteBatch() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package annot
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// layoutCache memoizes the rendered rows of annotation sets by their
+// fingerprints. The oldest entry is evicted if the cache is full.
+type layoutCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]cachedLayout
+	keys    []string
+}
+
+// cachedLayout is a cached layout. The segments of rows reference
+// annotations only by Index, so they can be reused for other annotations
+// with the same fingerprint.
+type cachedLayout struct {
+	rows      [][]Segment
+	collapsed []int
+}
+
+func newLayoutCache(size int) *layoutCache {
+	return &layoutCache{size: size, entries: map[string]cachedLayout{}}
+}
+
+func (c *layoutCache) get(key string) (cachedLayout, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cl, ok := c.entries[key]
+	return cl, ok
+}
+
+func (c *layoutCache) put(key string, cl cachedLayout) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.keys) == c.size {
+		delete(c.entries, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+	c.entries[key] = cl
+	c.keys = append(c.keys, key)
+}
+
+// WithLayoutCache memoizes the layouts of up to size annotation sets, e.g.
+// for user interfaces that render the same diagnostics on every frame.
+// Annotation sets with the same columns, ranges and lines in the same
+// order share a layout. The cache is safe for concurrent use. A size of 0
+// or less disables the cache.
+func WithLayoutCache(size int) Option {
+	return func(r *Renderer) {
+		r.cache = nil
+		if size > 0 {
+			r.cache = newLayoutCache(size)
+		}
+	}
+}
+
+// fingerprint returns a key of the arranged annotations and the width of
+// rows that contains everything that changes their layout.
+func fingerprint(annots []*Annot, width int) string {
+	b := &strings.Builder{}
+	writeInt := func(i int) {
+		b.WriteString(strconv.Itoa(i))
+		b.WriteByte(',')
+	}
+	writeString := func(s string) {
+		writeInt(len(s))
+		b.WriteString(s)
+	}
+	writeBool := func(v bool) {
+		if v {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+	}
+
+	writeInt(width)
+	for _, a := range annots {
+		writeInt(a.idx)
+		writeInt(a.Col)
+		writeInt(a.ColEnd)
+		writeInt(a.MaxWidth)
+		writeString(a.Bullet)
+		writeInt(a.HangingIndent)
+		writeBool(a.Paragraphs)
+		writeBool(a.Secondary)
+		writeInt(int(a.Emphasis))
+		writeInt(int(a.Severity))
+		writeInt(a.SeeLine)
+		writeInt(len(a.Lines))
+		for _, l := range a.Lines {
+			writeString(l)
+		}
+	}
+	return b.String()
+}
+
+// placeCached is like place but returns cached rows if the Renderer has a
+// layout cache.
+func (r *Renderer) placeCached(annots []*Annot, width int) ([][]Segment, error) {
+	if r.cache == nil {
+		return r.place(annots, width)
+	}
+	key := fingerprint(annots, width)
+	rows, ok := r.cache.cachedRows(key, annots)
+	if !ok {
+		var err error
+		if rows, err = r.place(annots, width); err != nil {
+			return nil, err
+		}
+		r.cache.cacheRows(key, annots, rows)
+	}
+	return rows, nil
+}
+
+// cachedRows returns the rows of the arranged annotations from the cache
+// and marks the collapsed annotations. It returns false if the rows are
+// not cached.
+func (c *layoutCache) cachedRows(key string, annots []*Annot) ([][]Segment, bool) {
+	cl, ok := c.get(key)
+	if !ok {
+		return nil, false
+	}
+
+	byIdx := make(map[int]*Annot, len(annots))
+	for _, a := range annots {
+		a.collapsed = false
+		byIdx[a.idx] = a
+	}
+	for _, idx := range cl.collapsed {
+		byIdx[idx].collapsed = true
+	}
+
+	rows := make([][]Segment, len(cl.rows))
+	for row, segments := range cl.rows {
+		rows[row] = make([]Segment, len(segments))
+		for sIdx, s := range segments {
+			s.Annot = byIdx[s.Index]
+			rows[row][sIdx] = s
+		}
+	}
+	return rows, true
+}
+
+// cacheRows caches the rows of the arranged annotations.
+func (c *layoutCache) cacheRows(key string, annots []*Annot, rows [][]Segment) {
+	cl := cachedLayout{rows: make([][]Segment, len(rows))}
+	for row, segments := range rows {
+		cl.rows[row] = make([]Segment, len(segments))
+		for sIdx, s := range segments {
+			s.Annot = nil
+			cl.rows[row][sIdx] = s
+		}
+	}
+	for _, a := range annots {
+		if a.collapsed {
+			cl.collapsed = append(cl.collapsed, a.idx)
+		}
+	}
+	c.put(key, cl)
+}
@@ -0,0 +1,74 @@
+package annot
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestWithLayoutCache(t *testing.T) {
+	newAnnots := func() []*Annot {
+		return []*Annot{
+			{Col: 4, Lines: []string{"line1", "line2", "line3"}},
+			{Col: 0, ColEnd: 2, Lines: []string{"line1"}},
+		}
+	}
+	r := NewRenderer(WithLayoutCache(1), WithCollapse(2))
+	want := NewRenderer(WithCollapse(2)).String(newAnnots()...)
+
+	first := newAnnots()
+	if got := r.String(first...); got != want {
+		t.Errorf("String() first = %v, want %v", got, want)
+	}
+
+	second := newAnnots()
+	passed := slices.Clone(second)
+	l, err := r.Layout(passed...)
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+	if got := l.String(); got != want {
+		t.Errorf("String() cached = %v, want %v", got, want)
+	}
+	l.ForEachRow(func(row int, segments []Segment) {
+		for _, s := range segments {
+			if s.Annot != second[s.Index] {
+				t.Errorf("ForEachRow() row %d segment %q has annotation of another call", row, s.Text)
+			}
+		}
+	})
+	if got := l.Collapsed(); !reflect.DeepEqual(got, []*Annot{second[0]}) {
+		t.Errorf("Collapsed() = %v, want %v", got, []*Annot{second[0]})
+	}
+
+	changed := newAnnots()
+	changed[0].Lines = []string{"other"}
+	if got, want := r.String(changed...), NewRenderer().String(newAnnots()[1], &Annot{Col: 4, Lines: []string{"other"}}); got != want {
+		t.Errorf("String() changed = %v, want %v", got, want)
+	}
+}
+
+func TestLayoutCache_put(t *testing.T) {
+	c := newLayoutCache(2)
+	c.put("a", cachedLayout{})
+	c.put("b", cachedLayout{})
+	c.put("a", cachedLayout{})
+	c.put("c", cachedLayout{})
+
+	for key, want := range map[string]bool{"a": false, "b": true, "c": true} {
+		if _, ok := c.get(key); ok != want {
+			t.Errorf("get(%q) = %v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a1 := []*Annot{{Col: 1, Lines: []string{"ab", "c"}}}
+	a2 := []*Annot{{Col: 1, Lines: []string{"a", "bc"}}}
+	if fingerprint(a1, 0) == fingerprint(a2, 0) {
+		t.Errorf("fingerprint() of different lines are equal")
+	}
+	if fingerprint(a1, 0) == fingerprint(a1, 10) {
+		t.Errorf("fingerprint() of different widths are equal")
+	}
+}
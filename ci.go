@@ -0,0 +1,163 @@
+package annot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteGitHubActions writes the diagnostics as GitHub Actions workflow
+// commands to a writer w, e.g.
+//
+//	::error file=main.go,line=3,col=5,endColumn=8::undefined: x
+//
+// Every annotation of a diagnostic becomes a command with the message of
+// the diagnostic followed by the lines of the annotation. Columns are
+// 1-based Unicode code point columns of the source line.
+func WriteGitHubActions(w io.Writer, diags ...*Diagnostic) error {
+	for _, d := range diags {
+		if len(d.Annots) == 0 {
+			if err := writeWorkflowCommand(w, d, d.Message, 0, 0); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, a := range d.Annots {
+			start, end := runeSpan(d.Source, a.Col, max(a.Col, a.ColEnd))
+			if err := writeWorkflowCommand(w, d, annotMessage(d, a), start+1, end); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeWorkflowCommand(w io.Writer, d *Diagnostic, message string, col, endCol int) error {
+	var props []string
+	if d.File != "" {
+		props = append(props, "file="+escapeWorkflowProperty(d.File))
+	}
+	if d.Line != 0 {
+		props = append(props, "line="+strconv.Itoa(d.Line))
+	}
+	if col != 0 {
+		props = append(props, "col="+strconv.Itoa(col), "endColumn="+strconv.Itoa(endCol))
+	}
+
+	cmd := "::" + workflowCommand(d.Severity)
+	if len(props) != 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+	_, err := fmt.Fprint(w, cmd+"::"+escapeWorkflowData(message)+"\n")
+	return err
+}
+
+func workflowCommand(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+func escapeWorkflowData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeWorkflowProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+type rdjson struct {
+	Source      rdjsonSource       `json:"source"`
+	Diagnostics []rdjsonDiagnostic `json:"diagnostics"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+}
+
+type rdjsonDiagnostic struct {
+	Message  string         `json:"message"`
+	Location rdjsonLocation `json:"location"`
+	Severity string         `json:"severity"`
+}
+
+type rdjsonLocation struct {
+	Path  string       `json:"path"`
+	Range *rdjsonRange `json:"range,omitempty"`
+}
+
+type rdjsonRange struct {
+	Start rdjsonPosition  `json:"start"`
+	End   *rdjsonPosition `json:"end,omitempty"`
+}
+
+type rdjsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+// WriteReviewdog writes the diagnostics in the reviewdog diagnostic
+// format (rdjson) of a tool called toolName to a writer w. Every
+// annotation of a diagnostic becomes a diagnostic with the message of the
+// diagnostic followed by the lines of the annotation. Columns are 1-based
+// UTF-8 byte columns of the source line and ranges end exclusively.
+func WriteReviewdog(w io.Writer, toolName string, diags ...*Diagnostic) error {
+	out := rdjson{
+		Source:      rdjsonSource{Name: toolName},
+		Diagnostics: []rdjsonDiagnostic{},
+	}
+	for _, d := range diags {
+		severity := rdjsonSeverity(d.Severity)
+		if len(d.Annots) == 0 {
+			rd := rdjsonDiagnostic{Message: d.Message, Location: rdjsonLocation{Path: d.File}, Severity: severity}
+			if d.Line != 0 {
+				rd.Location.Range = &rdjsonRange{Start: rdjsonPosition{Line: d.Line}}
+			}
+			out.Diagnostics = append(out.Diagnostics, rd)
+			continue
+		}
+		for _, a := range d.Annots {
+			start, end := byteSpan(d.Source, a.Col, max(a.Col, a.ColEnd))
+			out.Diagnostics = append(out.Diagnostics, rdjsonDiagnostic{
+				Message: annotMessage(d, a),
+				Location: rdjsonLocation{
+					Path: d.File,
+					Range: &rdjsonRange{
+						Start: rdjsonPosition{Line: d.Line, Column: start + 1},
+						End:   &rdjsonPosition{Line: d.Line, Column: end + 1},
+					},
+				},
+				Severity: severity,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func rdjsonSeverity(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "ERROR"
+	case SeverityWarning:
+		return "WARNING"
+	case SeverityInfo, SeverityHint:
+		return "INFO"
+	default:
+		return "UNKNOWN_SEVERITY"
+	}
+}
+
+// annotMessage returns the message of d followed by the lines of a.
+func annotMessage(d *Diagnostic, a *Annot) string {
+	return strings.Join(append([]string{d.Message}, a.Lines...), "\n")
+}
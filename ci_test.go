@@ -0,0 +1,152 @@
+package annot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteGitHubActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		diags []*Diagnostic
+		wantW string
+	}{
+		{
+			name:  "no diagnostics",
+			diags: nil,
+			wantW: `
+`,
+		},
+		{
+			name: "annotations",
+			diags: []*Diagnostic{
+				{
+					File:     "a,b:c.go",
+					Line:     3,
+					Source:   "漢 := x",
+					Severity: SeverityError,
+					Message:  "undefined: x",
+					Annots: []*Annot{
+						{Col: 0, ColEnd: 1, Lines: []string{"100%", "wide"}},
+						{Col: 6},
+					},
+				},
+			},
+			wantW: `
+::error file=a%2Cb%3Ac.go,line=3,col=1,endColumn=1::undefined: x%0A100%25%0Awide
+::error file=a%2Cb%3Ac.go,line=3,col=6,endColumn=6::undefined: x
+`,
+		},
+		{
+			name: "without annotations",
+			diags: []*Diagnostic{
+				{File: "main.go", Line: 1, Severity: SeverityWarning, Message: "unused"},
+				{Severity: SeverityHint, Message: "no file"},
+			},
+			wantW: `
+::warning file=main.go,line=1::unused
+::notice::no file
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := WriteGitHubActions(w, tt.diags...); err != nil {
+				t.Fatalf("WriteGitHubActions() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteGitHubActions() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestWriteReviewdog(t *testing.T) {
+	diags := []*Diagnostic{
+		{
+			File:     "main.go",
+			Line:     3,
+			Source:   "漢 := x",
+			Severity: SeverityError,
+			Message:  "undefined: x",
+			Annots:   []*Annot{{Col: 6, Lines: []string{"x"}}},
+		},
+		{File: "main.go", Line: 4, Severity: SeverityInfo, Message: "info"},
+	}
+	want := `{
+  "source": {
+    "name": "lint"
+  },
+  "diagnostics": [
+    {
+      "message": "undefined: x\nx",
+      "location": {
+        "path": "main.go",
+        "range": {
+          "start": {
+            "line": 3,
+            "column": 8
+          },
+          "end": {
+            "line": 3,
+            "column": 9
+          }
+        }
+      },
+      "severity": "ERROR"
+    },
+    {
+      "message": "info",
+      "location": {
+        "path": "main.go",
+        "range": {
+          "start": {
+            "line": 4
+          }
+        }
+      },
+      "severity": "INFO"
+    }
+  ]
+}
+`
+
+	w := &bytes.Buffer{}
+	if err := WriteReviewdog(w, "lint", diags...); err != nil {
+		t.Fatalf("WriteReviewdog() unexpected error = %v", err)
+	}
+	if got := w.String(); got != want {
+		t.Errorf("WriteReviewdog() got = %v, want %v", got, want)
+	}
+}
+
+func TestWriteGitHubActions_tabs(t *testing.T) {
+	w := &bytes.Buffer{}
+	if err := WriteGitHubActions(w, tabDiagnostics(t)...); err != nil {
+		t.Fatalf("WriteGitHubActions() unexpected error = %v", err)
+	}
+	want := "::error file=main.go,line=4,col=2,endColumn=2::declared and not used: x\n"
+	if got := w.String(); got != want {
+		t.Errorf("WriteGitHubActions() got = %q, want %q", got, want)
+	}
+}
+
+func TestWriteReviewdog_tabs(t *testing.T) {
+	w := &bytes.Buffer{}
+	if err := WriteReviewdog(w, "go", tabDiagnostics(t)...); err != nil {
+		t.Fatalf("WriteReviewdog() unexpected error = %v", err)
+	}
+	want := `"start": {
+            "line": 4,
+            "column": 2
+          },
+          "end": {
+            "line": 4,
+            "column": 3
+          }`
+	if !strings.Contains(w.String(), want) {
+		t.Errorf("WriteReviewdog() = %v, want it to contain %v", w.String(), want)
+	}
+}
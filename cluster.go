@@ -0,0 +1,72 @@
+package annot
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelMinAnnots is the default number of annotations from which
+// independent clusters are laid out in parallel.
+const parallelMinAnnots = 128
+
+// setRows sets the rows of the arranged annotations with created lines.
+// Independent clusters of many annotations are laid out in parallel,
+// unless the push hook could be called concurrently.
+func (r *Renderer) setRows(annots []*Annot) {
+	clusters := r.clusters(annots)
+	if len(annots) < r.parallelMin || len(clusters) == 1 || r.pushHook != nil {
+		for _, c := range clusters {
+			r.setClusterRows(c)
+		}
+		return
+	}
+
+	work := make(chan []*Annot)
+	wg := sync.WaitGroup{}
+	for range min(runtime.GOMAXPROCS(0), len(clusters)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range work {
+				r.setClusterRows(c)
+			}
+		}()
+	}
+	for _, c := range clusters {
+		work <- c
+	}
+	close(work)
+	wg.Wait()
+}
+
+// setClusterRows sets the rows of the annotations of a cluster.
+func (r *Renderer) setClusterRows(annots []*Annot) {
+	// Start with second last annotation index and decrement.
+	// The last annotation will always be on row=0 and needs
+	// no adjustment.
+	for aIdxDecr := len(annots) - 2; 0 <= aIdxDecr; aIdxDecr-- {
+		r.setRow(annots[aIdxDecr], annots[aIdxDecr+1:])
+	}
+}
+
+// clusters splits the arranged annotations with created lines into
+// clusters that cannot interact. A cluster ends before an annotation if
+// the lines of all annotations of the cluster end at least the gap left of
+// its stem, so no line of the cluster is ever moved down by it.
+func (r *Renderer) clusters(annots []*Annot) [][]*Annot {
+	var clusters [][]*Annot
+	start := 0
+	textEnd := 0
+	sp := r.spacing()
+	gap := max(sp.above, sp.linesAfterSecond)
+	for aIdx, a := range annots {
+		if aIdx > start && textEnd+gap <= a.pipeColIdx {
+			clusters = append(clusters, annots[start:aIdx])
+			start = aIdx
+		}
+		for _, l := range a.lines {
+			textEnd = max(textEnd, a.pipeColIdx+r.connWidth+l.length)
+		}
+	}
+	return append(clusters, annots[start:])
+}
@@ -0,0 +1,90 @@
+package annot
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRenderer_clusters(t *testing.T) {
+	tests := []struct {
+		name   string
+		annots []*Annot
+		want   []int
+	}{
+		{
+			name: "one cluster",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 9, Lines: []string{"line1"}},
+			},
+			want: []int{2},
+		},
+		{
+			name: "separated by two columns",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 10, Lines: []string{"line1"}},
+				{Col: 12, ColEnd: 20, Lines: []string{"line1"}},
+			},
+			want: []int{1, 2},
+		},
+		{
+			name: "earlier long line joins clusters",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"long line1"}},
+				{Col: 2, Lines: []string{"a"}},
+				{Col: 9, Lines: []string{"b"}},
+				{Col: 20, Lines: []string{"c"}},
+			},
+			want: []int{3, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer()
+			annots, _, err := r.arrange(tt.annots)
+			if err != nil {
+				t.Fatalf("arrange() unexpected error = %v", err)
+			}
+			for _, a := range annots {
+				r.createLines(a, 0)
+			}
+			var got []int
+			for _, c := range r.clusters(annots) {
+				got = append(got, len(c))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("clusters() sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderer_parallelClusters(t *testing.T) {
+	var annots []*Annot
+	for i := 0; i < 300; i++ {
+		lines := []string{"line1"}
+		for j := 0; j < i%4; j++ {
+			lines = append(lines, fmt.Sprintf("line%d of %d", j+2, i))
+		}
+		annots = append(annots, &Annot{Col: i/3*40 + i%3*4, Lines: lines})
+	}
+	r := NewRenderer()
+	arranged, _, _ := r.arrange(slices.Clone(annots))
+	for _, a := range arranged {
+		r.createLines(a, 0)
+	}
+	if n := len(r.clusters(arranged)); n < 50 {
+		t.Fatalf("clusters() = %d clusters, want at least 50", n)
+	}
+
+	r.parallelMin = math.MaxInt
+	want := r.String(annots...)
+
+	r.parallelMin = 1
+	if got := r.String(annots...); got != want {
+		t.Errorf("String() parallel = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+package annot
+
+// CompatLevel pins the layout to the behavior of a version of annot, so
+// that rendered output, e.g. in golden files, does not change when the
+// defaults of a newer version change.
+type CompatLevel int
+
+const (
+	// CompatLatest lays out annotations like the current version of annot.
+	CompatLatest CompatLevel = iota
+
+	// CompatV0 lays out annotations like annot v0.
+	CompatV0
+)
+
+// latestCompatLevel is the level CompatLatest stands for.
+const latestCompatLevel = CompatV0
+
+// maxCompatLevel is the highest known level.
+const maxCompatLevel = CompatV0
+
+// spacing is the space needed between lines of an annotation and the
+// stems and lines of annotations to the right, per section.
+type spacing struct {
+	above, lineTwo, linesAfterSecond, trailingSpaceLines int
+}
+
+// compatSpacing returns the spacing of a known CompatLevel. lineTwo is
+// added to the display width of the connector.
+func compatSpacing(level CompatLevel) spacing {
+	switch level {
+	case CompatV0:
+		return spacing{above: 2, lineTwo: 1, linesAfterSecond: 2, trailingSpaceLines: 1}
+	default:
+		return compatSpacing(latestCompatLevel)
+	}
+}
+
+// WithCompatLevel pins the layout to the behavior of the version level.
+// Unknown levels, e.g. of a newer version, are treated as CompatLatest.
+func WithCompatLevel(level CompatLevel) Option {
+	return func(r *Renderer) {
+		r.compat = level
+	}
+}
+
+// compatLevel returns the pinned level of the Renderer.
+func (r *Renderer) compatLevel() CompatLevel {
+	if r.compat <= CompatLatest || maxCompatLevel < r.compat {
+		return latestCompatLevel
+	}
+	return r.compat
+}
+
+// spacing returns the spacing of the gap or of the pinned level of the
+// Renderer.
+func (r *Renderer) spacing() spacing {
+	if r.gap > 0 {
+		return spacing{above: r.gap, lineTwo: r.gap - 1, linesAfterSecond: r.gap, trailingSpaceLines: r.gap - 1}
+	}
+	return compatSpacing(r.compatLevel())
+}
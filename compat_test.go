@@ -0,0 +1,53 @@
+package annot
+
+import "testing"
+
+func TestWithCompatLevel(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Lines: []string{"line1", "line2", "line3"}},
+		{Col: 4, Lines: []string{"line1", "line2"}},
+		{Col: 11, Lines: []string{"line1"}},
+	}
+	want := String(annots...)
+	for _, level := range []CompatLevel{CompatLatest, CompatV0, latestCompatLevel + 1} {
+		if got := NewRenderer(WithCompatLevel(level)).String(annots...); got != want {
+			t.Errorf("String() with level %d = %v, want %v", level, got, want)
+		}
+	}
+}
+
+func TestRenderer_compatLevel(t *testing.T) {
+	tests := []struct {
+		level CompatLevel
+		want  CompatLevel
+	}{
+		{level: CompatLatest, want: latestCompatLevel},
+		{level: CompatV0, want: CompatV0},
+		{level: -1, want: latestCompatLevel},
+	}
+	for _, tt := range tests {
+		if got := NewRenderer(WithCompatLevel(tt.level)).compatLevel(); got != tt.want {
+			t.Errorf("compatLevel() of %d = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestRenderer_spacing(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want spacing
+	}{
+		{name: "default", want: compatSpacing(latestCompatLevel)},
+		{name: "CompatV0", opts: []Option{WithCompatLevel(CompatV0)}, want: compatSpacing(CompatV0)},
+		{name: "gap 1", opts: []Option{WithGap(1)}, want: spacing{above: 1, linesAfterSecond: 1}},
+		{name: "ignored gap", opts: []Option{WithGap(0)}, want: compatSpacing(latestCompatLevel)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRenderer(tt.opts...).spacing(); got != tt.want {
+				t.Errorf("spacing() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
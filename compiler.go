@@ -0,0 +1,211 @@
+package annot
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// compilerLine matches file:line: message and file:line:col: message.
+var compilerLine = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?: (.*)$`)
+
+// ParseCompilerOutput parses diagnostics of the form file:line:col: message
+// as written by go build, go vet and gcc. A severity prefix like
+// "warning: " of gcc is removed from the message and sets the severity of
+// the diagnostic. Diagnostics without a severity prefix are errors.
+// Indented lines continue the message of the previous diagnostic and all
+// other lines are ignored.
+//
+// The source lines are read from fsys. A diagnostic with a column gets an
+// arrow annotation at the column of the byte column in the source line,
+// in which a tab counts as one column. Source keeps the tabs of the line,
+// which are expanded when the diagnostic is rendered. If fsys is nil or a
+// file cannot be read, the diagnostic has no source line and no annotation.
+func ParseCompilerOutput(r io.Reader, fsys fs.FS) ([]*Diagnostic, error) {
+	sources := newSourceCache(fsys)
+
+	var diags []*Diagnostic
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		text := strings.TrimSuffix(s.Text(), "\r")
+
+		if strings.HasPrefix(text, "\t") || strings.HasPrefix(text, " ") {
+			if len(diags) != 0 {
+				d := diags[len(diags)-1]
+				d.Message += "\n" + strings.TrimSpace(text)
+			}
+			continue
+		}
+
+		m := compilerLine.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(m[2])
+		severity, message := cutSeverity(m[4])
+		d := &Diagnostic{File: m[1], Line: lineNum, Severity: severity, Message: message}
+
+		if source, ok := sources.line(d.File, d.Line); ok {
+			d.Source = source
+			if m[3] != "" {
+				col, _ := strconv.Atoi(m[3])
+				d.Annots = []*Annot{{Col: byteColToSourceCol(source, col-1)}}
+			}
+		}
+		diags = append(diags, d)
+	}
+	return diags, s.Err()
+}
+
+// cutSeverity removes a severity prefix of gcc from message.
+func cutSeverity(message string) (Severity, string) {
+	for _, prefix := range []struct {
+		text     string
+		severity Severity
+	}{
+		{"fatal error: ", SeverityError},
+		{"error: ", SeverityError},
+		{"warning: ", SeverityWarning},
+		{"note: ", SeverityInfo},
+	} {
+		if rest, ok := strings.CutPrefix(message, prefix.text); ok {
+			return prefix.severity, rest
+		}
+	}
+	return SeverityError, message
+}
+
+// tabWidth is the distance of tab stops in source lines read from files.
+const tabWidth = 8
+
+// byteColToSourceCol returns the column of the 0-based byte column col of
+// source, in which a tab counts as one column. Columns after the end of
+// source count one column each. Diagnostics keep the tabs of their source
+// line, so exporters get the columns of the original line, and tabs are
+// only expanded by expandSource when diagnostics are rendered.
+func byteColToSourceCol(source string, col int) int {
+	col = max(col, 0)
+	if col > len(source) {
+		return sourceWidth(source) + col - len(source)
+	}
+	return sourceWidth(source[:col])
+}
+
+// sourceWidth returns the display width of s with a tab counting as one
+// column.
+func sourceWidth(s string) int {
+	return uniseg.StringWidth(s) + strings.Count(s, "\t")
+}
+
+// expandSource returns source with expanded tabs and copies of annots
+// with the columns of source, in which a tab counts as one column,
+// converted to the columns of the expanded line. A range that ends at a
+// tab ends at the last column of the expanded tab.
+func (r *Renderer) expandSource(source string, annots []*Annot) (string, []*Annot) {
+	if !strings.Contains(source, "\t") {
+		return source, annots
+	}
+
+	// starts and ends are the first and last expanded column of every
+	// column of source.
+	var starts, ends []int
+	width := 0
+	state := -1
+	rest := source
+	for rest != "" {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if cluster == "\t" {
+			w = tabWidth - width%tabWidth
+			starts = append(starts, width)
+			ends = append(ends, width+w-1)
+		} else {
+			for i := range w {
+				starts = append(starts, width+i)
+				ends = append(ends, width+i)
+			}
+		}
+		width += w
+	}
+	expand := func(col int, cols []int) int {
+		col -= r.origin
+		switch {
+		case col < 0:
+			return col + r.origin
+		case col < len(cols):
+			return cols[col] + r.origin
+		default:
+			return width + col - len(cols) + r.origin
+		}
+	}
+
+	expanded := make([]*Annot, len(annots))
+	for aIdx, a := range annots {
+		if a == nil {
+			continue
+		}
+		c := *a
+		c.Col = expand(a.Col, starts)
+		if a.ColEnd != 0 {
+			c.ColEnd = expand(a.ColEnd, ends)
+		}
+		expanded[aIdx] = &c
+	}
+	return expandTabs(source), expanded
+}
+
+// expandTabs replaces the tabs of s with spaces up to the next tab stop.
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	b := &strings.Builder{}
+	width := 0
+	for _, part := range strings.SplitAfter(s, "\t") {
+		text, isTab := strings.CutSuffix(part, "\t")
+		b.WriteString(text)
+		width += uniseg.StringWidth(text)
+		if isTab {
+			spaces := tabWidth - width%tabWidth
+			b.WriteString(strings.Repeat(" ", spaces))
+			width += spaces
+		}
+	}
+	return b.String()
+}
+
+// sourceCache reads lines of files of a file system once.
+type sourceCache struct {
+	fsys  fs.FS
+	files map[string][]string
+}
+
+func newSourceCache(fsys fs.FS) *sourceCache {
+	return &sourceCache{fsys: fsys, files: map[string][]string{}}
+}
+
+// line returns the 1-based line lineNum of file with its tabs.
+func (c *sourceCache) line(file string, lineNum int) (string, bool) {
+	if c.fsys == nil {
+		return "", false
+	}
+	lines, ok := c.files[file]
+	if !ok {
+		b, err := fs.ReadFile(c.fsys, strings.TrimPrefix(path.Clean(file), "/"))
+		if err == nil {
+			lines = strings.Split(string(b), "\n")
+		}
+		c.files[file] = lines
+	}
+	if lineNum < 1 || len(lines) < lineNum {
+		return "", false
+	}
+	return strings.TrimSuffix(lines[lineNum-1], "\r"), true
+}
@@ -0,0 +1,227 @@
+package annot
+
+import (
+	"bytes"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseCompilerOutput(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":  {Data: []byte("package main\n\nfunc main() {\n\t漢 := x\n}\n")},
+		"lib/a.c":  {Data: []byte("int a;\r\nint b = c;\r\n")},
+		"other.go": {Data: []byte("package other\n")},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		fsys   fs.FS
+		want   []*Diagnostic
+	}{
+		{
+			name:   "empty output",
+			output: "",
+			fsys:   fsys,
+			want:   nil,
+		},
+		{
+			name: "go build",
+			output: `# example
+./main.go:4:9: undefined: x
+./main.go:4:2: declared and not used: 漢
+	hint
+`,
+			fsys: fsys,
+			want: []*Diagnostic{
+				{
+					File: "./main.go", Line: 4, Source: "\t漢 := x", Severity: SeverityError,
+					Message: "undefined: x", Annots: []*Annot{{Col: 7}},
+				},
+				{
+					File: "./main.go", Line: 4, Source: "\t漢 := x", Severity: SeverityError,
+					Message: "declared and not used: 漢\nhint", Annots: []*Annot{{Col: 1}},
+				},
+			},
+		},
+		{
+			name: "gcc",
+			output: `lib/a.c:2:9: warning: implicit declaration
+lib/a.c:1:1: note: previous definition
+lib/a.c:1: fatal error: no column
+`,
+			fsys: fsys,
+			want: []*Diagnostic{
+				{
+					File: "lib/a.c", Line: 2, Source: "int b = c;", Severity: SeverityWarning,
+					Message: "implicit declaration", Annots: []*Annot{{Col: 8}},
+				},
+				{
+					File: "lib/a.c", Line: 1, Source: "int a;", Severity: SeverityInfo,
+					Message: "previous definition", Annots: []*Annot{{Col: 0}},
+				},
+				{
+					File: "lib/a.c", Line: 1, Source: "int a;", Severity: SeverityError,
+					Message: "no column",
+				},
+			},
+		},
+		{
+			name: "missing sources",
+			output: `missing.go:1:1: a
+other.go:9:1: b
+`,
+			fsys: fsys,
+			want: []*Diagnostic{
+				{File: "missing.go", Line: 1, Severity: SeverityError, Message: "a"},
+				{File: "other.go", Line: 9, Severity: SeverityError, Message: "b"},
+			},
+		},
+		{
+			name:   "without file system",
+			output: "main.go:1:1: a\n",
+			fsys:   nil,
+			want: []*Diagnostic{
+				{File: "main.go", Line: 1, Severity: SeverityError, Message: "a"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCompilerOutput(strings.NewReader(tt.output), tt.fsys)
+			if err != nil {
+				t.Fatalf("ParseCompilerOutput() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCompilerOutput() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "no tabs", s: "a b", want: "a b"},
+		{name: "leading tabs", s: "\t\tx", want: "                x"},
+		{name: "tab after text", s: "ab\tc", want: "ab      c"},
+		{name: "tab after wide character", s: "漢\tc", want: "漢      c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandTabs(tt.s); got != tt.want {
+				t.Errorf("expandTabs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteColToSourceCol(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		col    int
+		want   int
+	}{
+		{name: "ascii", source: "abc", col: 2, want: 2},
+		{name: "after wide character", source: "漢x", col: 3, want: 2},
+		{name: "after end", source: "ab", col: 4, want: 4},
+		{name: "negative", source: "ab", col: -1, want: 0},
+		{name: "after tabs", source: "\ta\tb", col: 3, want: 3},
+		{name: "after end with tab", source: "\t", col: 2, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := byteColToSourceCol(tt.source, tt.col); got != tt.want {
+				t.Errorf("byteColToSourceCol() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderer_expandSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		source     string
+		annots     []*Annot
+		wantSource string
+		want       []*Annot
+	}{
+		{
+			name:       "without tabs",
+			source:     "a b",
+			annots:     []*Annot{{Col: 2}},
+			wantSource: "a b",
+			want:       []*Annot{{Col: 2}},
+		},
+		{
+			name:       "tabs and wide character",
+			source:     "\t漢\tx",
+			annots:     []*Annot{{Col: 0}, {Col: 2, ColEnd: 3}, {Col: 4, ColEnd: 6}, nil},
+			wantSource: "        漢      x",
+			want:       []*Annot{{Col: 0}, {Col: 9, ColEnd: 15}, {Col: 16, ColEnd: 18}, nil},
+		},
+		{
+			name:       "origin",
+			opts:       []Option{WithOrigin(1)},
+			source:     "\tx",
+			annots:     []*Annot{{Col: 2}, {Col: 0}},
+			wantSource: "        x",
+			want:       []*Annot{{Col: 9}, {Col: 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSource, got := NewRenderer(tt.opts...).expandSource(tt.source, tt.annots)
+			if gotSource != tt.wantSource {
+				t.Errorf("expandSource() gotSource = %q, want %q", gotSource, tt.wantSource)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandSource() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// tabDiagnostics returns the diagnostics of compiler output for the
+// tab-indented line "\tx := 1".
+func tabDiagnostics(t *testing.T) []*Diagnostic {
+	t.Helper()
+	fsys := fstest.MapFS{"main.go": {Data: []byte("package main\n\nfunc main() {\n\tx := 1\n}\n")}}
+	diags, err := ParseCompilerOutput(strings.NewReader("main.go:4:2: declared and not used: x\n"), fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return diags
+}
+
+func TestParseCompilerOutput_tabs(t *testing.T) {
+	diags := tabDiagnostics(t)
+	if d := diags[0]; d.Source != "\tx := 1" || d.Annots[0].Col != 1 {
+		t.Errorf("ParseCompilerOutput() = %+v, want source with tab and Col 1", d)
+	}
+	w := &bytes.Buffer{}
+	if err := WriteReport(w, diags...); err != nil {
+		t.Fatalf("WriteReport() unexpected error = %v", err)
+	}
+	want := `
+main.go: 1 error
+
+error: declared and not used: x
+4 │         x := 1
+  │         ↑
+  │         └─ 
+
+1 problem (1 error)
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("WriteReport() gotW = %v, want %v", gotW, want)
+	}
+}
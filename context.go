@@ -0,0 +1,95 @@
+package annot
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// WriteContext writes the 1-based line of src with n lines of context
+// before and after it. See [Renderer.WriteContext].
+func WriteContext(w io.Writer, src io.Reader, line, col, n int, labels ...string) error {
+	return NewRenderer().WriteContext(w, src, line, col, n, labels...)
+}
+
+// WriteContext writes the 1-based line of src with n lines of context
+// before and after it and a line number gutter. The line is followed by
+// an annotation with the labels as lines like in WriteLint: the 1-based
+// byte column col gets an arrow annotation and a col of 0 annotates the
+// whole line. Tabs of the lines are expanded.
+//
+//	41 │ func main() {
+//	42 │         fmt.Println(x)
+//	   │                     ↑
+//	   │                     └─ undefined: x
+//	43 │ }
+func (r *Renderer) WriteContext(w io.Writer, src io.Reader, line, col, n int, labels ...string) error {
+	r = r.coloredFor(w)
+	first := max(line-n, 1)
+	var lines []string
+	lineCount := 0
+	// A bufio.Reader reads lines of any length unlike a bufio.Scanner.
+	br := bufio.NewReader(src)
+	for lineCount < line+n {
+		text, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if text == "" {
+			break
+		}
+		lineCount++
+		if lineCount >= first {
+			lines = append(lines, strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r"))
+		}
+		if err != nil {
+			break
+		}
+	}
+	if line < 1 || line > lineCount {
+		return newLineOutOfRangeError(line, lineCount)
+	}
+
+	source := lines[line-first]
+	a := lineAnnot(source)
+	if col > 0 {
+		a = &Annot{Col: byteColToSourceCol(source, col-1)}
+	}
+	if a == nil {
+		a = &Annot{}
+	}
+	a.Lines = labels
+
+	b := &strings.Builder{}
+	gutterWidth := lineNumWidth(first + len(lines) - 1)
+	for lIdx, l := range lines {
+		e := Entry{LineNum: first + lIdx, Line: expandTabs(l)}
+		if e.LineNum == line {
+			e.Line, e.Annots = r.expandSource(l, []*Annot{a})
+		}
+		if err := r.writeEntry(b, gutterWidth, e); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteFileContext writes the 1-based line of the file name with n lines
+// of context before and after it. See [Renderer.WriteContext].
+func WriteFileContext(w io.Writer, name string, line, col, n int, labels ...string) error {
+	return NewRenderer().WriteFileContext(w, name, line, col, n, labels...)
+}
+
+// WriteFileContext writes the 1-based line of the file name with n lines
+// of context before and after it like WriteContext.
+func (r *Renderer) WriteFileContext(w io.Writer, name string, line, col, n int, labels ...string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.WriteContext(w, f, line, col, n, labels...)
+}
@@ -0,0 +1,131 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_WriteContext(t *testing.T) {
+	const src = "package main\n\nfunc main() {\n\tfmt.Println(x)\n}\n"
+	tests := []struct {
+		name    string
+		src     string
+		line    int
+		col     int
+		n       int
+		labels  []string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name:   "column",
+			src:    src,
+			line:   4,
+			col:    14,
+			n:      1,
+			labels: []string{"undefined: x"},
+			wantW: `
+3 │ func main() {
+4 │         fmt.Println(x)
+  │                     ↑
+  │                     └─ undefined: x
+5 │ }
+`,
+		},
+		{
+			name:   "whole tab-indented line",
+			src:    src,
+			line:   4,
+			labels: []string{"call"},
+			wantW: `
+4 │         fmt.Println(x)
+  │         └─────┬──────┘
+  │               └─ call
+`,
+		},
+		{
+			name:   "whole line and context cut at start of src",
+			src:    src,
+			line:   1,
+			n:      2,
+			labels: []string{"a", "b"},
+			wantW: `
+1 │ package main
+  │ └────┬─────┘
+  │      └─ a
+  │         b
+2 │ 
+3 │ func main() {
+`,
+		},
+		{
+			name:   "gutter width of last line",
+			src:    strings.Repeat("x\n", 10),
+			line:   9,
+			n:      1,
+			labels: []string{"y"},
+			wantW: `
+ 8 │ x
+ 9 │ x
+   │ ↑
+   │ └─ y
+10 │ x
+`,
+		},
+		{
+			name:    "line out of range",
+			src:     src,
+			line:    6,
+			wantW:   "\n",
+			wantErr: &LineOutOfRangeError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := NewRenderer().WriteContext(w, strings.NewReader(tt.src), tt.line, tt.col, tt.n, tt.labels...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("WriteContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteContext() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestRenderer_WriteContext_longLine(t *testing.T) {
+	long := strings.Repeat("x", 70*1024)
+	src := "a\n" + long + "\nb"
+	w := &bytes.Buffer{}
+	if err := NewRenderer().WriteContext(w, strings.NewReader(src), 3, 1, 1, "here"); err != nil {
+		t.Fatalf("WriteContext() unexpected error = %v", err)
+	}
+	want := "2 │ " + long + "\n3 │ b\n  │ ↑\n  │ └─ here\n"
+	if gotW := w.String(); gotW != want {
+		t.Errorf("WriteContext() gotW without the long line = %v, want %v",
+			strings.Replace(gotW, long, "…", 1), strings.Replace(want, long, "…", 1))
+	}
+}
+
+func TestWriteFileContext(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(name, []byte("[server]\nport = 80\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w := &bytes.Buffer{}
+	if err := WriteFileContext(w, name, 2, 8, 1, "privileged"); err != nil {
+		t.Fatalf("WriteFileContext() unexpected error = %v", err)
+	}
+	want := "1 │ [server]\n2 │ port = 80\n  │        ↑\n  │        └─ privileged\n"
+	if got := w.String(); got != want {
+		t.Errorf("WriteFileContext() = %q, want %q", got, want)
+	}
+	if err := WriteFileContext(w, filepath.Join(t.TempDir(), "missing"), 1, 0, 0); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("WriteFileContext() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
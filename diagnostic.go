@@ -0,0 +1,109 @@
+package annot
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Severity is the importance of a diagnostic. Higher severities are more
+// important.
+type Severity int
+
+const (
+	// SeverityNone is the zero value for a diagnostic without a severity.
+	SeverityNone Severity = iota
+	SeverityHint
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the lower case name of s, e.g. "warning".
+func (s Severity) String() string {
+	switch s {
+	case SeverityHint:
+		return "hint"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "note"
+	}
+}
+
+// MarshalText returns the name of s, e.g. "warning".
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText sets s to the severity of a name returned by String. It
+// returns an *UnknownSeverityError for other names.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for severity := SeverityNone; severity <= SeverityError; severity++ {
+		if severity.String() == string(text) {
+			*s = severity
+			return nil
+		}
+	}
+	return newUnknownSeverityError(string(text))
+}
+
+// plural returns the name of s for n diagnostics, e.g. "2 warnings".
+func (s Severity) plural(n int) string {
+	name := s.String()
+	if n != 1 && s != SeverityInfo {
+		name += "s"
+	}
+	return strconv.Itoa(n) + " " + name
+}
+
+// Diagnostic is a message about a source line of a file with annotations
+// of the line.
+type Diagnostic struct {
+	// File is the name of the file of Source.
+	File string
+
+	// Line is the 1-based line number of Source. A Line of 0 is not shown.
+	Line int
+
+	// Source is the annotated line.
+	Source string
+
+	// Severity is the importance of the diagnostic.
+	Severity Severity
+
+	// Message describes the diagnostic.
+	Message string
+
+	// Annots are the annotations of Source.
+	Annots []*Annot
+
+	// Related are secondary annotations of Source, e.g. "expected due to
+	// this". They are rendered together with Annots like annotations with
+	// Secondary set.
+	Related []*Annot
+
+	// Fixes are suggested replacements of the text of Source. A report
+	// shows Source with the fixes applied below the annotations.
+	Fixes []Fix
+}
+
+// annots returns Annots and copies of Related with Secondary set.
+func (d *Diagnostic) annots() []*Annot {
+	if len(d.Related) == 0 {
+		return d.Annots
+	}
+	annots := slices.Clone(d.Annots)
+	for _, a := range d.Related {
+		if a != nil {
+			c := *a
+			c.Secondary = true
+			a = &c
+		}
+		annots = append(annots, a)
+	}
+	return annots
+}
@@ -0,0 +1,198 @@
+package annot
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// WriteDiagram renders annotations of a block of ASCII art, e.g. a
+// keyboard layout or a small diagram, and writes the block followed by
+// the rendered annotations to a writer w. See [Renderer.WriteDiagram].
+func WriteDiagram(w io.Writer, art string, annots ...*Annot) error {
+	return NewRenderer().WriteDiagram(w, art, annots...)
+}
+
+// WriteDiagram renders annotations of a block of ASCII art, e.g. a
+// keyboard layout or a small diagram, and writes the block followed by
+// the rendered annotations to a writer w. Line is the row of the target
+// of an annotation in the block, Col and ColEnd are its columns.
+//
+// The arrowhead or range of an annotation is drawn in the row below its
+// target and its stem goes straight down through the rows below to the
+// annotation below the block. Arrowheads, ranges and stems are only drawn
+// in blank cells of the block, so stems pass behind the art. A stem that
+// passes a range of another annotation crosses it with ┼ and passes
+// behind its corners and arrowheads. Of annotations whose
+// stems have the same column only the first one is rendered or, with
+// WithStrict, a *DuplicateColError is returned.
+//
+//	┌───┬───┐
+//	│ Q │ W │
+//	└───┴───┘
+//	  │   │
+//	  │   └─ up
+//	  │
+//	  └─ quit
+func (r *Renderer) WriteDiagram(w io.Writer, art string, annots ...*Annot) error {
+	lines := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+	grid := make([][]string, len(lines))
+	for lIdx, l := range lines {
+		grid[lIdx] = r.cells(l)
+	}
+
+	targets := make([]diagramTarget, 0, len(annots))
+	stemCols := map[int]int{}
+	for aIdx, a := range annots {
+		if err := checkFields(aIdx+1, a); err != nil {
+			return err
+		}
+		switch {
+		case a.Col < r.origin:
+			return newColOutOfRangeError(aIdx+1, a.Col, r.origin)
+		case a.ColEnd != 0 && a.Col >= a.ColEnd:
+			return newColExceedsColEndError(aIdx+1, a.Col, a.ColEnd)
+		}
+		t := r.diagramTarget(a)
+		if t.lIdx < 0 || len(lines) <= t.lIdx {
+			return newLineOutOfRangeError(a.Line, len(lines))
+		}
+		if first, ok := stemCols[t.pipeCol]; ok {
+			if r.strict {
+				return newDuplicateColError(first+1, annots[first].Col, aIdx+1, a.Col)
+			}
+			continue
+		}
+		stemCols[t.pipeCol] = aIdx
+		targets = append(targets, t)
+	}
+
+	// Draw all arrowheads and ranges before the stems, so stems cross
+	// them instead of being blocked by them.
+	crossable := map[[2]int]bool{}
+	for _, t := range targets {
+		if t.lIdx == len(lines)-1 {
+			continue
+		}
+		row := t.lIdx + 1
+		if blankCells(grid[row], t.col, uniseg.StringWidth(t.marker)) {
+			grid[row] = setCells(grid[row], t.col, t.marker)
+			for col, cell := range r.cells(t.marker) {
+				if cell == r.glyphs.Horizontal || cell == r.glyphs.DashedHorizontal || cell == r.glyphs.HeavyHorizontal {
+					crossable[[2]int{row, t.col + col}] = true
+				}
+			}
+		}
+	}
+	below := make([]*Annot, len(targets))
+	for tIdx, t := range targets {
+		if t.lIdx == len(lines)-1 {
+			below[tIdx] = t.a
+			continue
+		}
+		for row := t.lIdx + 1; row < len(grid); row++ {
+			switch {
+			case crossable[[2]int{row, t.pipeCol}]:
+				grid[row] = setCells(grid[row], t.pipeCol, r.glyphs.Crossing)
+			case blankCells(grid[row], t.pipeCol, 1):
+				grid[row] = setCells(grid[row], t.pipeCol, r.pipe(t.a))
+			}
+		}
+		below[tIdx] = &Annot{
+			Col:           t.pipeCol + r.origin,
+			Lines:         t.a.Lines,
+			MaxWidth:      t.a.MaxWidth,
+			Bullet:        t.a.Bullet,
+			HangingIndent: t.a.HangingIndent,
+			Paragraphs:    t.a.Paragraphs,
+			Secondary:     t.a.Secondary,
+			Emphasis:      t.a.Emphasis,
+			Severity:      t.a.Severity,
+			Style:         t.a.Style,
+			SeeLine:       t.a.SeeLine,
+			stemOnly:      true,
+		}
+	}
+
+	if _, err := fmt.Fprint(w, joinCells(grid)); err != nil {
+		return err
+	}
+	return r.Write(w, below...)
+}
+
+// diagramTarget is the target of an annotation in a block of WriteDiagram
+// with columns counted from 0.
+type diagramTarget struct {
+	a       *Annot
+	lIdx    int
+	col     int
+	pipeCol int
+	marker  string
+}
+
+func (r *Renderer) diagramTarget(a *Annot) diagramTarget {
+	t := diagramTarget{a: a, lIdx: a.Line - r.origin, col: a.Col - r.origin}
+	t.pipeCol = t.col
+	t.marker = r.arrowhead(a)
+	if r.noArrowheads || a.Secondary {
+		t.marker = r.pipe(a)
+	}
+	if a.ColEnd != 0 {
+		colEnd := a.ColEnd - r.origin
+		t.pipeCol = (t.col + colEnd) / 2
+		t.marker = r.rangeText(t.col, colEnd, t.pipeCol, "", r.horizontal(a))
+	}
+	return t
+}
+
+// cells returns the grapheme clusters of s by display column. A wide
+// character is followed by empty cells for the columns it covers.
+func (r *Renderer) cells(s string) []string {
+	var cells []string
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		cells = append(cells, g.Str())
+		for i := 1; i < r.stringWidth(g.Str()); i++ {
+			cells = append(cells, "")
+		}
+	}
+	return cells
+}
+
+// blankCells reports whether the width cells starting at col are spaces
+// or after the end of cells.
+func blankCells(cells []string, col, width int) bool {
+	for c := col; c < col+width; c++ {
+		if c < len(cells) && cells[c] != " " {
+			return false
+		}
+	}
+	return true
+}
+
+// setCells sets the cells starting at col to the characters of text and
+// pads cells with spaces if needed.
+func setCells(cells []string, col int, text string) []string {
+	for _, ch := range text {
+		for len(cells) <= col {
+			cells = append(cells, " ")
+		}
+		cells[col] = string(ch)
+		col++
+	}
+	return cells
+}
+
+// joinCells returns the rows of cells each followed by a newline.
+func joinCells(grid [][]string) string {
+	b := &strings.Builder{}
+	for _, cells := range grid {
+		for _, c := range cells {
+			b.WriteString(c)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,158 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteDiagram(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		art     string
+		annots  []*Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "keys of a keyboard",
+			art: `┌───┬───┐
+│ Q │ W │
+└───┴───┘
+`,
+			annots: []*Annot{
+				{Line: 1, Col: 2, Lines: []string{"quit"}},
+				{Line: 1, Col: 6, Lines: []string{"up"}},
+			},
+			wantW: `
+┌───┬───┐
+│ Q │ W │
+└───┴───┘
+  │   │
+  │   └─ up
+  │
+  └─ quit
+`,
+		},
+		{
+			name: "stems in blank cells",
+			art: `A   B
+ CCC
+`,
+			annots: []*Annot{
+				{Line: 0, Col: 0, ColEnd: 2, Lines: []string{"range"}},
+				{Line: 0, Col: 4, Lines: []string{"b"}},
+			},
+			wantW: `
+A   B
+ CCC↑
+ │  │
+ │  └─ b
+ │
+ └─ range
+`,
+		},
+		{
+			name: "range marker in blank row",
+			art:  "abcde\n\nxyz",
+			annots: []*Annot{
+				{Line: 0, Col: 1, ColEnd: 3, Lines: []string{"bcd"}},
+				{Line: 2, Col: 0, ColEnd: 1, Lines: []string{"last row"}},
+			},
+			wantW: `
+abcde
+ └┬┘
+xyz
+├┘│
+│ └─ bcd
+│
+└─ last row
+`,
+		},
+		{
+			name: "stem crosses range of lower row",
+			art:  "abc\n\n\nxyz\n",
+			annots: []*Annot{
+				{Line: 0, Col: 1, Lines: []string{"b"}},
+				{Line: 1, Col: 0, ColEnd: 4, Lines: []string{"range"}},
+			},
+			wantW: `
+abc
+ ↑
+└┼┬─┘
+xyz
+ ││
+ │└─ range
+ │
+ └─ b
+`,
+		},
+		{
+			name: "same stem column in different rows",
+			art:  "ab\n\ncd\n\n",
+			annots: []*Annot{
+				{Line: 2, Col: 1, Lines: []string{"d"}},
+				{Line: 0, Col: 1, Lines: []string{"b"}},
+			},
+			wantW: `
+ab
+
+cd
+ ↑
+ │
+ └─ d
+`,
+		},
+		{
+			name: "strict with same stem column",
+			opts: []Option{WithStrict()},
+			art:  "ab\n\ncd\n\n",
+			annots: []*Annot{
+				{Line: 0, Col: 0, ColEnd: 2},
+				{Line: 2, Col: 1},
+			},
+			wantErr: &DuplicateColError{},
+		},
+		{
+			name: "one-based rows and columns",
+			opts: []Option{WithOrigin(1)},
+			art:  "ab\n  ",
+			annots: []*Annot{
+				{Line: 1, Col: 2, Lines: []string{"b"}},
+			},
+			wantW: `
+ab
+ ↑
+ │
+ └─ b
+`,
+		},
+		{
+			name: "line out of range",
+			art:  "a",
+			annots: []*Annot{
+				{Line: 1, Col: 0},
+			},
+			wantErr: &LineOutOfRangeError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := NewRenderer(tt.opts...).WriteDiagram(w, tt.art, tt.annots...)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("WriteDiagram() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteDiagram() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteDiagram() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package annot
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// WriteDoc renders annotations of the lines of a multi-line text, e.g. a
+// code snippet or a config file, and writes every line followed by its
+// rendered annotations to a writer w. See [Renderer.WriteDoc].
+func WriteDoc(w io.Writer, text string, annots ...*Annot) error {
+	return NewRenderer().WriteDoc(w, text, annots...)
+}
+
+// WriteDoc renders annotations of the lines of a multi-line text, e.g. a
+// code snippet or a config file, and writes every line followed by its
+// rendered annotations to a writer w. Line is the line of the text of an
+// annotation and is counted from the origin like Col. Like WriteLine,
+// WriteDoc returns a *ColExceedsLineError if Col or ColEnd is not a column
+// of the line and fits arrows and ranges to wide characters. Nothing is
+// written if an annotation is invalid.
+//
+//	port = 8080
+//	       └┬─┘
+//	        └─ unprivileged
+//	host = "localhost"
+//	        ↑
+//	        └─ quoted
+func (r *Renderer) WriteDoc(w io.Writer, text string, annots ...*Annot) error {
+	lines, layouts, err := r.DocLayouts(text, annots...)
+	if lines == nil {
+		return err
+	}
+	for lIdx, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+		if layouts[lIdx] == nil {
+			continue
+		}
+		if err := layouts[lIdx].Write(w); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// DocLayouts returns the lines of text and the layouts of the annotations
+// of every line like WriteDoc, e.g. to write them in another format. The
+// layout of a line without annotations is nil. If an annotation is
+// invalid, nil slices and the error are returned. If the Renderer skips
+// invalid annotations, the layouts of the valid annotations and one
+// *SkippedError of the annotations skipped on all lines are returned.
+func (r *Renderer) DocLayouts(text string, annots ...*Annot) ([]string, []*Layout, error) {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	byLine := make([][]*Annot, len(lines))
+	var skipped []Skipped
+	for aIdx, a := range annots {
+		fitted, err := r.docAnnot(aIdx+1, a, lines)
+		if err != nil {
+			if !r.skipInvalid {
+				return nil, nil, err
+			}
+			skipped = append(skipped, Skipped{Annot: a, Err: err})
+			continue
+		}
+		lIdx := a.Line - r.origin
+		byLine[lIdx] = append(byLine[lIdx], fitted)
+	}
+
+	layouts := make([]*Layout, len(lines))
+	for lIdx, lineAnnots := range byLine {
+		if len(lineAnnots) == 0 {
+			continue
+		}
+		l, err := r.Layout(lineAnnots...)
+		if l == nil {
+			return nil, nil, err
+		}
+		var skippedErr *SkippedError
+		if errors.As(err, &skippedErr) {
+			skipped = append(skipped, skippedErr.Skipped()...)
+		}
+		layouts[lIdx] = l
+	}
+	if len(skipped) != 0 {
+		return lines, layouts, newSkippedError(skipped)
+	}
+	return lines, layouts, nil
+}
+
+// docAnnot returns the annotation a at annotPos fitted to its line of
+// lines or an error if a is invalid.
+func (r *Renderer) docAnnot(annotPos int, a *Annot, lines []string) (*Annot, error) {
+	if err := checkFields(annotPos, a); err != nil {
+		return nil, err
+	}
+	lIdx := a.Line - r.origin
+	if lIdx < 0 || len(lines) <= lIdx {
+		return nil, newLineOutOfRangeError(a.Line, len(lines))
+	}
+	return r.fitLine(annotPos, a, r.cells(lines[lIdx]))
+}
@@ -0,0 +1,139 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRenderer_WriteDoc(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		text    string
+		annots  []*Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "annotations of different lines",
+			text: "[server]\nport = 8080\nhost = \"localhost\"\n",
+			annots: []*Annot{
+				{Line: 2, Col: 8, Lines: []string{"quoted"}},
+				{Line: 1, Col: 7, ColEnd: 10, Lines: []string{"unprivileged"}},
+			},
+			wantW: `
+[server]
+port = 8080
+       └┬─┘
+        └─ unprivileged
+host = "localhost"
+        ↑
+        └─ quoted
+`,
+		},
+		{
+			name: "origin",
+			opts: []Option{WithOrigin(1)},
+			text: "a\nb",
+			annots: []*Annot{
+				{Line: 2, Col: 1, Lines: []string{"b"}},
+			},
+			wantW: `
+a
+b
+↑
+└─ b
+`,
+		},
+		{
+			name: "wide character",
+			text: "a\n世界",
+			annots: []*Annot{
+				{Line: 1, Col: 3, Lines: []string{"界"}},
+			},
+			wantW: `
+a
+世界
+  ↑
+  └─ 界
+`,
+		},
+		{
+			name: "line out of range",
+			text: "a\nb",
+			annots: []*Annot{
+				{Line: 2, Col: 0},
+			},
+			wantW:   "\n",
+			wantErr: &LineOutOfRangeError{},
+		},
+		{
+			name: "column after end of line",
+			text: "abc\nd",
+			annots: []*Annot{
+				{Line: 0, Col: 2},
+				{Line: 1, Col: 2},
+			},
+			wantW:   "\n",
+			wantErr: &ColExceedsLineError{},
+		},
+		{
+			name:    "nil annotation",
+			text:    "a",
+			annots:  []*Annot{nil},
+			wantW:   "\n",
+			wantErr: &NilAnnotError{},
+		},
+		{
+			name: "invalid annotations",
+			text: "abc\ndef",
+			annots: []*Annot{
+				{Line: 1, Col: 0, ColEnd: 2},
+				{Line: 1, Col: 1},
+			},
+			wantW:   "\n",
+			wantErr: &OverlapError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := NewRenderer(tt.opts...).WriteDoc(w, tt.text, tt.annots...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("WriteDoc() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteDoc() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestRenderer_DocLayouts_skipInvalid(t *testing.T) {
+	annots := []*Annot{
+		{Line: 0, Col: 0, ColEnd: 2, Lines: []string{"abc"}},
+		{Line: 0, Col: 1, Lines: []string{"overlap"}},
+		{Line: 1, Col: 9, Lines: []string{"right of line"}},
+		{Line: 2, Col: 0, ColEnd: 2, Lines: []string{"ghi"}},
+		{Line: 2, Col: 1, Lines: []string{"overlap"}},
+		{Line: 5, Col: 0, Lines: []string{"out of range"}},
+	}
+	lines, layouts, err := NewRenderer(WithSkipInvalid()).DocLayouts("abc\ndef\nghi\n", annots...)
+	var skippedErr *SkippedError
+	if !errors.As(err, &skippedErr) {
+		t.Fatalf("DocLayouts() error = %v, want %v", err, &SkippedError{})
+	}
+	var got []*Annot
+	for _, s := range skippedErr.Skipped() {
+		got = append(got, s.Annot)
+	}
+	want := []*Annot{annots[2], annots[5], annots[1], annots[4]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Skipped() got = %v, want %v", got, want)
+	}
+	if len(lines) != 3 || layouts[0] == nil || layouts[1] != nil || layouts[2] == nil {
+		t.Errorf("DocLayouts() got = %v, %v", lines, layouts)
+	}
+}
@@ -0,0 +1,31 @@
+package annot
+
+// Emphasis is the weight of an annotation in a hierarchy of annotations
+// that does not require colors. Ranges of emphasized annotations are
+// drawn with heavy lines like └━┬━┘ and the lines of their text are bold.
+// The lines of the text of de-emphasized annotations are dim.
+type Emphasis int
+
+const (
+	// EmphasisLow de-emphasizes an annotation, e.g. a hint.
+	EmphasisLow Emphasis = -1
+
+	// EmphasisNormal is the zero value and does not change an annotation.
+	EmphasisNormal Emphasis = 0
+
+	// EmphasisHigh emphasizes an annotation, e.g. the cause of an error.
+	EmphasisHigh Emphasis = 1
+)
+
+// intensity returns the style of the text of an annotation with the
+// emphasis e.
+func (e Emphasis) intensity() Style {
+	switch {
+	case e < EmphasisNormal:
+		return Dim
+	case e > EmphasisNormal:
+		return Bold
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,30 @@
+package annot
+
+import "testing"
+
+func TestAnnot_Emphasis(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, ColEnd: 4, Emphasis: EmphasisHigh, Lines: []string{"cause"}},
+		{Col: 6, Emphasis: EmphasisLow, Lines: []string{"hint"}},
+		{Col: 8, Lines: []string{"x"}},
+	}
+	got := NewRenderer(WithForceColor()).String(annots...)
+	want := "" +
+		"└━┬━┘ ↑ ↑\n" +
+		"  │   │ └─ x\n" +
+		"  │   │\n" +
+		"  │   └─ \x1b[2mhint\x1b[0m\n" +
+		"  │\n" +
+		"  └─ \x1b[1mcause\x1b[0m\n"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	got = NewRenderer(WithForceColor(), WithTheme(ThemeASCII), WithStyles(Styles{Text: Italic})).String(annots[0])
+	want = "\\=+=/\n  `-- \x1b[3;1mcause\x1b[0m\n"
+	if got != want {
+		t.Errorf("String() with ASCII theme and styles = %q, want %q", got, want)
+	}
+	if plain := NewRenderer(WithCanonical()).String(annots[1]); plain != "      ↑\n      └─ hint\n" {
+		t.Errorf("String() in canonical mode = %q, want the layout without intensity", plain)
+	}
+}
@@ -0,0 +1,62 @@
+package annot
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EnvVar is a parsed KEY=VALUE line, e.g. an element of os.Environ.
+type EnvVar struct {
+	// Line is the parsed line.
+	Line string
+
+	// Key and Value are the fields called "key" and "value".
+	Key, Value Field
+}
+
+// ParseEnvVar parses a KEY=VALUE line. It returns a *SyntaxError if the
+// line has no "=" or an empty key.
+func ParseEnvVar(line string) (EnvVar, error) {
+	eqIdx := strings.Index(line, "=")
+	switch {
+	case eqIdx == -1:
+		return EnvVar{}, newSyntaxError(sourceWidth(line), `missing "="`)
+	case eqIdx == 0:
+		return EnvVar{}, newSyntaxError(0, "empty key")
+	}
+	return EnvVar{
+		Line:  line,
+		Key:   newField("key", line, 0, eqIdx),
+		Value: newField("value", line, eqIdx+1, len(line)),
+	}, nil
+}
+
+// Entries returns the entries of a list in the value separated by sep, e.g.
+// the directories of PATH separated by string(os.PathListSeparator). The
+// fields are called "entry 1", "entry 2" and so on. Empty entries have an
+// arrow anchor at their position.
+func (v EnvVar) Entries(sep string) []Field {
+	var entries []Field
+	start := len(v.Line) - len(v.Value.Text)
+	for eIdx, entry := range strings.Split(v.Value.Text, sep) {
+		entries = append(entries, newField("entry "+strconv.Itoa(eIdx+1), v.Line, start, start+len(entry)))
+		start += len(entry) + len(sep)
+	}
+	return entries
+}
+
+// Duplicates returns an annotation for every entry of a list in the value
+// separated by sep that equals a previous entry, e.g. a directory that is
+// twice in PATH.
+func (v EnvVar) Duplicates(sep string) []*Annot {
+	var annots []*Annot
+	first := map[string]Field{}
+	for _, entry := range v.Entries(sep) {
+		if f, ok := first[entry.Text]; ok {
+			annots = append(annots, entry.Annot("duplicate of "+f.Name))
+			continue
+		}
+		first[entry.Text] = entry
+	}
+	return annots
+}
@@ -0,0 +1,100 @@
+package annot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseEnvVar(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "key and value",
+			line: "GOFLAGS=-mod=mod",
+			wantW: `
+GOFLAGS=-mod=mod
+└──┬──┘ └──┬───┘
+   └─ key  └─ value
+`,
+		},
+		{
+			name: "empty value",
+			line: "A=",
+			wantW: `
+A=
+↑ ↑
+│ └─ value
+│
+└─ key
+`,
+		},
+		{
+			name: "missing equals sign",
+			line: "GOFLAGS",
+			wantW: `
+GOFLAGS
+       ↑
+       └─ missing "="
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "empty key",
+			line:    "=value",
+			wantErr: &SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseEnvVar(tt.line)
+			var annots []*Annot
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Fatalf("ParseEnvVar() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantW == "" {
+					return
+				}
+				var syntaxErr *SyntaxError
+				errors.As(err, &syntaxErr)
+				annots = []*Annot{syntaxErr.Annot()}
+			} else {
+				if err != nil {
+					t.Fatalf("ParseEnvVar() unexpected error = %v", err)
+				}
+				annots = LabelFields(v.Key, v.Value)
+			}
+			if gotW := "\n" + tt.line + "\n" + String(annots...); gotW != tt.wantW {
+				t.Errorf("ParseEnvVar() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestEnvVar_Duplicates(t *testing.T) {
+	v, err := ParseEnvVar("PATH=/usr/bin::/bin:/usr/bin")
+	if err != nil {
+		t.Fatalf("ParseEnvVar() unexpected error = %v", err)
+	}
+
+	entries := v.Entries(":")
+	if len(entries) != 4 || entries[1].Text != "" || entries[1].Col != 14 || entries[1].ColEnd != 0 {
+		t.Errorf("Entries() = %+v, want empty second entry at column 14", entries)
+	}
+
+	annots := append(v.Duplicates(":"), entries[1].Annot("empty entry is the working directory"))
+	wantW := `
+PATH=/usr/bin::/bin:/usr/bin
+              ↑     └──┬───┘
+              │        └─ duplicate of entry 1
+              │
+              └─ empty entry is the working directory
+`
+	if gotW := "\n" + v.Line + "\n" + String(annots...); gotW != wantW {
+		t.Errorf("Duplicates() gotW = %v, want %v", gotW, wantW)
+	}
+}
@@ -3,19 +3,35 @@ package annot
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type OverlapError struct {
-	colEnd, firstAnnotPos, secondAnnotCol int
+	firstAnnotPos int
+	first, second *Annot
 }
 
-func newOverlapError(colEnd, firstAnnotPos, secondAnnotCol int) *OverlapError {
-	return &OverlapError{colEnd, firstAnnotPos, secondAnnotCol}
+func newOverlapError(firstAnnotPos int, first, second *Annot) *OverlapError {
+	return &OverlapError{firstAnnotPos, first, second}
 }
 
 func (e *OverlapError) Error() string {
-	return fmt.Sprintf("annot: ColEnd %d of %d. annotation overlaps with Col %d of %d. annotation",
-		e.colEnd, e.firstAnnotPos, e.secondAnnotCol, e.firstAnnotPos+1)
+	return fmt.Sprintf("annot: %d. annotation%s at Col %d to ColEnd %d overlaps with %d. annotation%s at Col %d",
+		e.firstAnnotPos, label(e.first), e.first.Col, e.first.ColEnd,
+		e.firstAnnotPos+1, label(e.second), e.second.Col)
+}
+
+// Annots returns the overlapping annotations in the order of their columns.
+func (e *OverlapError) Annots() (first, second *Annot) {
+	return e.first, e.second
+}
+
+// label returns the quoted first line of a for error messages.
+func label(a *Annot) string {
+	if len(a.Lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %q", a.Lines[0])
 }
 
 func (e *OverlapError) Is(target error) bool {
@@ -40,3 +56,419 @@ func (e *ColExceedsColEndError) Is(target error) bool {
 	var overlapError *ColExceedsColEndError
 	return errors.As(target, &overlapError)
 }
+
+type AnchorSyntaxError struct {
+	pos    int
+	reason string
+}
+
+func newAnchorSyntaxError(pos int, reason string) *AnchorSyntaxError {
+	return &AnchorSyntaxError{pos, reason}
+}
+
+func (e *AnchorSyntaxError) Error() string {
+	return fmt.Sprintf("annot: anchor marker at byte %d %s", e.pos, e.reason)
+}
+
+func (e *AnchorSyntaxError) Is(target error) bool {
+	var anchorSyntaxError *AnchorSyntaxError
+	return errors.As(target, &anchorSyntaxError)
+}
+
+type SyntaxError struct {
+	col    int
+	reason string
+}
+
+func newSyntaxError(col int, reason string) *SyntaxError {
+	return &SyntaxError{col, reason}
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("annot: syntax error at column %d: %s", e.col, e.reason)
+}
+
+func (e *SyntaxError) Is(target error) bool {
+	var syntaxError *SyntaxError
+	return errors.As(target, &syntaxError)
+}
+
+// Annot returns an annotation of the reason at the column where parsing
+// failed.
+func (e *SyntaxError) Annot() *Annot {
+	return &Annot{Col: e.col, Lines: []string{e.reason}}
+}
+
+type UnknownSeverityError struct {
+	name string
+}
+
+func newUnknownSeverityError(name string) *UnknownSeverityError {
+	return &UnknownSeverityError{name}
+}
+
+func (e *UnknownSeverityError) Error() string {
+	return fmt.Sprintf("annot: severity %q does not exist", e.name)
+}
+
+func (e *UnknownSeverityError) Is(target error) bool {
+	var unknownSeverityError *UnknownSeverityError
+	return errors.As(target, &unknownSeverityError)
+}
+
+type UnknownAnchorError struct {
+	name string
+}
+
+func newUnknownAnchorError(name string) *UnknownAnchorError {
+	return &UnknownAnchorError{name}
+}
+
+func (e *UnknownAnchorError) Error() string {
+	return fmt.Sprintf("annot: anchor %q does not exist", e.name)
+}
+
+func (e *UnknownAnchorError) Is(target error) bool {
+	var unknownAnchorError *UnknownAnchorError
+	return errors.As(target, &unknownAnchorError)
+}
+
+type UnknownNodeError struct {
+	path string
+}
+
+func newUnknownNodeError(path string) *UnknownNodeError {
+	return &UnknownNodeError{path}
+}
+
+func (e *UnknownNodeError) Error() string {
+	return fmt.Sprintf("annot: tree node %q does not exist", e.path)
+}
+
+func (e *UnknownNodeError) Is(target error) bool {
+	var unknownNodeError *UnknownNodeError
+	return errors.As(target, &unknownNodeError)
+}
+
+type LineOutOfRangeError struct {
+	line, lineCount int
+}
+
+func newLineOutOfRangeError(line, lineCount int) *LineOutOfRangeError {
+	return &LineOutOfRangeError{line, lineCount}
+}
+
+func (e *LineOutOfRangeError) Error() string {
+	return fmt.Sprintf("annot: line %d is out of range of %d lines", e.line, e.lineCount)
+}
+
+func (e *LineOutOfRangeError) Is(target error) bool {
+	var lineOutOfRangeError *LineOutOfRangeError
+	return errors.As(target, &lineOutOfRangeError)
+}
+
+type DuplicateColError struct {
+	firstAnnotPos, firstCol, annotPos, col int
+}
+
+func newDuplicateColError(firstAnnotPos, firstCol, annotPos, col int) *DuplicateColError {
+	return &DuplicateColError{firstAnnotPos, firstCol, annotPos, col}
+}
+
+func (e *DuplicateColError) Error() string {
+	if e.firstCol == e.col {
+		return fmt.Sprintf("annot: %d. and %d. annotation have the same Col %d",
+			e.firstAnnotPos, e.annotPos, e.col)
+	}
+	return fmt.Sprintf("annot: %d. annotation at Col %d and %d. annotation at Col %d are duplicates within the window",
+		e.firstAnnotPos, e.firstCol, e.annotPos, e.col)
+}
+
+func (e *DuplicateColError) Is(target error) bool {
+	var duplicateColError *DuplicateColError
+	return errors.As(target, &duplicateColError)
+}
+
+// Skipped is an annotation that was not rendered and the reason why.
+type Skipped struct {
+	Annot *Annot
+	Err   error
+}
+
+type SkippedError struct {
+	skipped []Skipped
+}
+
+func newSkippedError(skipped []Skipped) *SkippedError {
+	return &SkippedError{skipped}
+}
+
+func (e *SkippedError) Error() string {
+	msgs := make([]string, len(e.skipped))
+	for i, s := range e.skipped {
+		msgs[i] = s.Err.Error()
+	}
+	return fmt.Sprintf("annot: skipped %d invalid annotation(s): %s",
+		len(e.skipped), strings.Join(msgs, "; "))
+}
+
+// Skipped returns the skipped annotations. Annotations that are nil or
+// have fields out of range come first in the order they were passed in,
+// the others follow in the order of their columns.
+func (e *SkippedError) Skipped() []Skipped {
+	return e.skipped
+}
+
+func (e *SkippedError) Unwrap() []error {
+	errs := make([]error, len(e.skipped))
+	for i, s := range e.skipped {
+		errs[i] = s.Err
+	}
+	return errs
+}
+
+func (e *SkippedError) Is(target error) bool {
+	var skippedError *SkippedError
+	return errors.As(target, &skippedError)
+}
+
+type ColOutOfRangeError struct {
+	annotPos, col, minCol int
+}
+
+func newColOutOfRangeError(annotPos, col, minCol int) *ColOutOfRangeError {
+	return &ColOutOfRangeError{annotPos, col, minCol}
+}
+
+func (e *ColOutOfRangeError) Error() string {
+	return fmt.Sprintf("annot: in %d. annotation Col %d needs to be at least %d",
+		e.annotPos, e.col, e.minCol)
+}
+
+func (e *ColOutOfRangeError) Is(target error) bool {
+	var colOutOfRangeError *ColOutOfRangeError
+	return errors.As(target, &colOutOfRangeError)
+}
+
+type ColExceedsLineError struct {
+	annotPos   int
+	field      string
+	value, end int
+}
+
+func newColExceedsLineError(annotPos int, field string, value, end int) *ColExceedsLineError {
+	return &ColExceedsLineError{annotPos, field, value, end}
+}
+
+func (e *ColExceedsLineError) Error() string {
+	return fmt.Sprintf("annot: in %d. annotation %s %d needs to be lower than the end %d of the line",
+		e.annotPos, e.field, e.value, e.end)
+}
+
+func (e *ColExceedsLineError) Is(target error) bool {
+	var colExceedsLineError *ColExceedsLineError
+	return errors.As(target, &colExceedsLineError)
+}
+
+type WideCharError struct {
+	annotPos int
+	field    string
+	value    int
+}
+
+func newWideCharError(annotPos int, field string, value int) *WideCharError {
+	return &WideCharError{annotPos, field, value}
+}
+
+func (e *WideCharError) Error() string {
+	return fmt.Sprintf("annot: in %d. annotation %s %d is in the middle of a wide character",
+		e.annotPos, e.field, e.value)
+}
+
+func (e *WideCharError) Is(target error) bool {
+	var wideCharError *WideCharError
+	return errors.As(target, &wideCharError)
+}
+
+type StructTagError struct {
+	field, reason string
+}
+
+func newStructTagError(field, reason string) *StructTagError {
+	return &StructTagError{field, reason}
+}
+
+func (e *StructTagError) Error() string {
+	if e.field == "" {
+		return "annot: " + e.reason
+	}
+	return fmt.Sprintf("annot: invalid annot tag of field %s: %s", e.field, e.reason)
+}
+
+func (e *StructTagError) Is(target error) bool {
+	var structTagError *StructTagError
+	return errors.As(target, &structTagError)
+}
+
+type FixError struct {
+	fixPos, col int
+	reason      string
+}
+
+func newFixError(fixPos, col int, reason string) *FixError {
+	return &FixError{fixPos, col, reason}
+}
+
+func (e *FixError) Error() string {
+	return fmt.Sprintf("annot: %d. fix at Col %d %s", e.fixPos, e.col, e.reason)
+}
+
+func (e *FixError) Is(target error) bool {
+	var fixError *FixError
+	return errors.As(target, &fixError)
+}
+
+type NilAnnotError struct {
+	annotPos int
+}
+
+func newNilAnnotError(annotPos int) *NilAnnotError {
+	return &NilAnnotError{annotPos}
+}
+
+func (e *NilAnnotError) Error() string {
+	return fmt.Sprintf("annot: %d. annotation is nil", e.annotPos)
+}
+
+func (e *NilAnnotError) Is(target error) bool {
+	var nilAnnotError *NilAnnotError
+	return errors.As(target, &nilAnnotError)
+}
+
+type NilDiagnosticError struct {
+	diagPos int
+}
+
+func newNilDiagnosticError(diagPos int) *NilDiagnosticError {
+	return &NilDiagnosticError{diagPos}
+}
+
+func (e *NilDiagnosticError) Error() string {
+	return fmt.Sprintf("annot: %d. diagnostic is nil", e.diagPos)
+}
+
+func (e *NilDiagnosticError) Is(target error) bool {
+	var nilDiagnosticError *NilDiagnosticError
+	return errors.As(target, &nilDiagnosticError)
+}
+
+type FieldRangeError struct {
+	annotPos        int
+	field           string
+	value, maxValue int
+}
+
+func newFieldRangeError(annotPos int, field string, value, maxValue int) *FieldRangeError {
+	return &FieldRangeError{annotPos, field, value, maxValue}
+}
+
+func (e *FieldRangeError) Error() string {
+	return fmt.Sprintf("annot: in %d. annotation %s %d needs to be between 0 and %d",
+		e.annotPos, e.field, e.value, e.maxValue)
+}
+
+func (e *FieldRangeError) Is(target error) bool {
+	var fieldRangeError *FieldRangeError
+	return errors.As(target, &fieldRangeError)
+}
+
+type StyleError struct {
+	annotPos int
+	style    Style
+}
+
+func newStyleError(annotPos int, style Style) *StyleError {
+	return &StyleError{annotPos, style}
+}
+
+func (e *StyleError) Error() string {
+	return fmt.Sprintf("annot: in %d. annotation Style %q needs to be digits separated by \";\"",
+		e.annotPos, string(e.style))
+}
+
+func (e *StyleError) Is(target error) bool {
+	var styleError *StyleError
+	return errors.As(target, &styleError)
+}
+
+type InvariantError struct {
+	row, col int
+	reason   string
+}
+
+func newInvariantError(row, col int, reason string) *InvariantError {
+	return &InvariantError{row, col, reason}
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("annot: invalid layout in row %d at column %d: %s", e.row, e.col, e.reason)
+}
+
+func (e *InvariantError) Is(target error) bool {
+	var invariantError *InvariantError
+	return errors.As(target, &invariantError)
+}
+
+// Errorf returns an error whose message is the formatted message followed
+// by source and the rendered annotation a. The formatted error wraps
+// errors of %w verbs like fmt.Errorf.
+func Errorf(source string, a *Annot, format string, args ...any) error {
+	return &AnnotatedError{source: source, annot: a, err: fmt.Errorf(format, args...)}
+}
+
+type AnnotatedError struct {
+	source string
+	annot  *Annot
+	err    error
+}
+
+func (e *AnnotatedError) Error() string {
+	return e.err.Error() + "\n" + e.source + "\n" + strings.TrimSuffix(String(e.annot), "\n")
+}
+
+// Source returns the annotated source line.
+func (e *AnnotatedError) Source() string {
+	return e.source
+}
+
+// Annot returns the annotation of the source line.
+func (e *AnnotatedError) Annot() *Annot {
+	return e.annot
+}
+
+func (e *AnnotatedError) Unwrap() error {
+	return e.err
+}
+
+func (e *AnnotatedError) Is(target error) bool {
+	var annotatedError *AnnotatedError
+	return errors.As(target, &annotatedError)
+}
+
+type ClippedError struct {
+	annotPos, col, width int
+}
+
+func newClippedError(annotPos, col, width int) *ClippedError {
+	return &ClippedError{annotPos, col, width}
+}
+
+func (e *ClippedError) Error() string {
+	return fmt.Sprintf("annot: %d. annotation at column %d is clipped by the width %d",
+		e.annotPos, e.col, e.width)
+}
+
+func (e *ClippedError) Is(target error) bool {
+	var clippedError *ClippedError
+	return errors.As(target, &clippedError)
+}
@@ -0,0 +1,87 @@
+package annot
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestErrorf(t *testing.T) {
+	err := Errorf("x := 1 +", &Annot{Col: 7, Lines: []string{"missing operand"}}, "parse: %w", io.ErrUnexpectedEOF)
+
+	want := `parse: unexpected EOF
+x := 1 +
+       ↑
+       └─ missing operand`
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Errorf() does not wrap %v", io.ErrUnexpectedEOF)
+	}
+	var annotatedErr *AnnotatedError
+	if !errors.As(err, &annotatedErr) {
+		t.Fatalf("Errorf() error = %T, want %T", err, annotatedErr)
+	}
+	if annotatedErr.Source() != "x := 1 +" || annotatedErr.Annot().Col != 7 {
+		t.Errorf("Source(), Annot() = %v, %v", annotatedErr.Source(), annotatedErr.Annot())
+	}
+}
+
+func TestErrorf_wrapsAll(t *testing.T) {
+	err := Errorf("x := 1 +", &Annot{Col: 7}, "parse: %w: %w", io.ErrUnexpectedEOF, io.ErrShortBuffer)
+
+	for _, target := range []error{io.ErrUnexpectedEOF, io.ErrShortBuffer} {
+		if !errors.Is(err, target) {
+			t.Errorf("Errorf() does not wrap %v", target)
+		}
+	}
+}
+
+func TestDuplicateColError(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		annots []*Annot
+		want   string
+	}{
+		{
+			name:   "same column",
+			annots: []*Annot{{Col: 3}, {Col: 3}},
+			want:   "annot: 1. and 2. annotation have the same Col 3",
+		},
+		{
+			name:   "within duplicate window",
+			opts:   []Option{WithDuplicateWindow(1)},
+			annots: []*Annot{{Col: 3}, {Col: 4}},
+			want:   "annot: 1. annotation at Col 3 and 2. annotation at Col 4 are duplicates within the window",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRenderer(append(tt.opts, WithStrict())...).Layout(tt.annots...)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Layout() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlapError(t *testing.T) {
+	first := &Annot{Col: 1, ColEnd: 5, Lines: []string{"adjective", "more"}}
+	second := &Annot{Col: 3}
+
+	_, err := NewRenderer().Layout(second, first)
+
+	want := `annot: 1. annotation "adjective" at Col 1 to ColEnd 5 overlaps with 2. annotation at Col 3`
+	if err == nil || err.Error() != want {
+		t.Fatalf("Layout() error = %v, want %v", err, want)
+	}
+	var overlapErr *OverlapError
+	if !errors.As(err, &overlapErr) {
+		t.Fatalf("Layout() error = %T, want %T", err, overlapErr)
+	}
+	if gotFirst, gotSecond := overlapErr.Annots(); gotFirst != first || gotSecond != second {
+		t.Errorf("Annots() = %v, %v, want %v, %v", gotFirst, gotSecond, first, second)
+	}
+}
@@ -29,3 +29,15 @@ func Example() {
 	//                              the theoretical or practical understanding
 	//                              of a subject.
 }
+
+func ExampleParseAnchors() {
+	line, anchors, _ := annot.ParseAnchors("The {{adj:greatest}} enemy of {{noun:knowledge}}.")
+	adj, _ := anchors.Annot("adj", "adjective")
+	noun, _ := anchors.Annot("noun", "noun")
+	fmt.Println(line)
+	fmt.Println(annot.String(adj, noun))
+	// Output:
+	// The greatest enemy of knowledge.
+	//     └──┬───┘          └───┬───┘
+	//        └─ adjective       └─ noun
+}
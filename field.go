@@ -0,0 +1,29 @@
+package annot
+
+// Field is a parsed part of a line, e.g. the key of a KEY=VALUE line. The
+// embedded Anchor is the span of the field in the line, so Annot annotates
+// the field.
+type Field struct {
+	Anchor
+
+	// Name describes the field, e.g. "key".
+	Name string
+
+	// Text is the text of the field in the line.
+	Text string
+}
+
+// newField returns the field called name of the bytes from start to the
+// exclusive end of line.
+func newField(name, line string, start, end int) Field {
+	return Field{Anchor: spanAnchor(line, start, end), Name: name, Text: line[start:end]}
+}
+
+// LabelFields returns an annotation with the name of every field.
+func LabelFields(fields ...Field) []*Annot {
+	annots := make([]*Annot, 0, len(fields))
+	for _, f := range fields {
+		annots = append(annots, f.Annot(f.Name))
+	}
+	return annots
+}
@@ -0,0 +1,92 @@
+package annot
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Fix is a suggested replacement of the text of a line, e.g. to preview
+// the auto-fix of a linter.
+type Fix struct {
+	// Col is the column where the replaced text starts. Columns are
+	// counted like Col of annotations.
+	Col int
+
+	// Width is the display width of the replaced text. A Width of 0
+	// inserts Replacement before Col.
+	Width int
+
+	// Replacement is the text that replaces the columns from Col to
+	// Col+Width.
+	Replacement string
+}
+
+// ApplyFixes returns source with the fixes applied. See
+// [Renderer.ApplyFixes].
+func ApplyFixes(source string, fixes ...Fix) (string, error) {
+	return NewRenderer().ApplyFixes(source, fixes...)
+}
+
+// ApplyFixes returns source with the fixes applied. The order of fixes
+// does not matter, except for insertions at the same column, which are
+// inserted in the given order. Fixes must not overlap and must replace
+// whole characters of source, otherwise ApplyFixes returns a *FixError
+// and source is not changed.
+func (r *Renderer) ApplyFixes(source string, fixes ...Fix) (string, error) {
+	cells := r.cells(source)
+	order := make([]int, len(fixes))
+	for fIdx, f := range fixes {
+		col := f.Col - r.origin
+		switch {
+		case f.Width < 0:
+			return "", newFixError(fIdx+1, f.Col, "has a negative width")
+		case col < 0 || len(cells) < col+f.Width:
+			return "", newFixError(fIdx+1, f.Col, "is not within the line")
+		case col < len(cells) && cells[col] == "",
+			col+f.Width < len(cells) && cells[col+f.Width] == "":
+			return "", newFixError(fIdx+1, f.Col, "splits a wide character")
+		}
+		order[fIdx] = fIdx
+	}
+	// Insertions come before replacements at the same column, so they do
+	// not overlap.
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Or(cmp.Compare(fixes[a].Col, fixes[b].Col), cmp.Compare(fixes[a].Width, fixes[b].Width))
+	})
+
+	b := &strings.Builder{}
+	end := 0
+	for oIdx, fIdx := range order {
+		f := fixes[fIdx]
+		col := f.Col - r.origin
+		if col < end {
+			return "", newFixError(fIdx+1, f.Col, "overlaps the "+strconv.Itoa(order[oIdx-1]+1)+". fix")
+		}
+		b.WriteString(strings.Join(cells[end:col], ""))
+		b.WriteString(f.Replacement)
+		end = col + f.Width
+	}
+	b.WriteString(strings.Join(cells[end:], ""))
+	return b.String(), nil
+}
+
+// writeFixes writes source and source with the fixes applied as removed
+// and added line like a diff.
+func (r *Renderer) writeFixes(b *strings.Builder, gutterWidth int, source string, fixes []Fix) error {
+	fixed, err := r.ApplyFixes(source, fixes...)
+	if err != nil {
+		return err
+	}
+	writeDiffLine(b, gutterWidth, "-", expandTabs(source))
+	writeDiffLine(b, gutterWidth, "+", expandTabs(fixed))
+	return nil
+}
+
+func writeDiffLine(b *strings.Builder, gutterWidth int, sign, line string) {
+	b.WriteString(strings.Repeat(" ", gutterWidth))
+	b.WriteString(" " + sign + " ")
+	b.WriteString(line)
+	b.WriteString("\n")
+}
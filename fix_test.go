@@ -0,0 +1,101 @@
+package annot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenderer_ApplyFixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		source  string
+		fixes   []Fix
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "no fixes",
+			source: "abc",
+			want:   "abc",
+		},
+		{
+			name:   "replace, insert and delete",
+			source: "abcdef",
+			fixes: []Fix{
+				{Col: 4, Width: 2},
+				{Col: 1, Width: 2, Replacement: "X"},
+				{Col: 0, Replacement: ">"},
+				{Col: 6, Replacement: "<"},
+			},
+			want: ">aXd<",
+		},
+		{
+			name:   "insertion before replacement at same column",
+			source: "abc",
+			fixes: []Fix{
+				{Col: 1, Width: 1, Replacement: "B"},
+				{Col: 1, Replacement: "_"},
+			},
+			want: "a_Bc",
+		},
+		{
+			name:   "wide characters and origin",
+			opts:   []Option{WithOrigin(1)},
+			source: "a世界b",
+			fixes:  []Fix{{Col: 4, Width: 2, Replacement: "x"}},
+			want:   "a世xb",
+		},
+		{
+			name:    "negative width",
+			source:  "abc",
+			fixes:   []Fix{{Col: 1, Width: -1}},
+			wantErr: &FixError{},
+		},
+		{
+			name:    "after end of line",
+			source:  "abc",
+			fixes:   []Fix{{Col: 2, Width: 2}},
+			wantErr: &FixError{},
+		},
+		{
+			name:    "splits wide character",
+			source:  "世界",
+			fixes:   []Fix{{Col: 1, Width: 2}},
+			wantErr: &FixError{},
+		},
+		{
+			name:    "overlap",
+			source:  "abcdef",
+			fixes:   []Fix{{Col: 3, Width: 2}, {Col: 0, Width: 4}},
+			wantErr: &FixError{},
+		},
+		{
+			name:    "insertion inside replacement",
+			source:  "abcdef",
+			fixes:   []Fix{{Col: 1, Width: 3}, {Col: 2, Replacement: "x"}},
+			wantErr: &FixError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRenderer(tt.opts...).ApplyFixes(tt.source, tt.fixes...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("ApplyFixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyFixes() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	got, err := ApplyFixes("if x == nil {", Fix{Col: 3, Width: 1, Replacement: "err"}, Fix{Col: 8, Width: 3, Replacement: "nil"})
+	if err != nil {
+		t.Fatalf("ApplyFixes() unexpected error = %v", err)
+	}
+	if want := "if err == nil {"; got != want {
+		t.Errorf("ApplyFixes() got = %q, want %q", got, want)
+	}
+}
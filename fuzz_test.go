@@ -0,0 +1,64 @@
+package annot
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func FuzzWrite(f *testing.F) {
+	f.Add(0, 0, 0, 0, "line1", "", false, 4, 0, 0, 0, 0, 0, 0, "")
+	f.Add(3, 5, 2, 1, "a long line", "• ", true, 0, 3, 0, int(SeverityError), int(EmphasisHigh), 42, 1, "1;31")
+	f.Add(-1, -5, -2, -1, "", "", false, -4, -1, 0, int(EmphasisLow)-1, -1, -1, -1, "\x1b[2J")
+	f.Add(1<<40, 1<<41, 1<<30, 1<<30, "x", "\x00", true, 1<<40, 0, 0, 1<<40, 1<<40, 1<<40, 1<<40, "38;5;999")
+	f.Add(MaxCol, 0, 1, MaxCol, "漢字\xff", "", false, MaxCol-2, MaxCol-1, 7, int(SeverityError)+1, int(EmphasisHigh)+1, MaxCol, MaxCol, ";;")
+	f.Fuzz(func(t *testing.T, col, colEnd, maxWidth, hangingIndent int, line, bullet string, paragraphs bool, col2, colEnd2, width,
+		severity, emphasis, seeLine, lineNum int, style string,
+	) {
+		annots := []*Annot{
+			{
+				Col: col, ColEnd: colEnd, MaxWidth: maxWidth, HangingIndent: hangingIndent, Bullet: bullet, Paragraphs: paragraphs,
+				Lines: []string{line, line}, Severity: Severity(severity), Emphasis: Emphasis(emphasis), SeeLine: seeLine,
+				Line: lineNum, Style: Style(style),
+			},
+			{Col: col2, ColEnd: colEnd2, Lines: []string{line}, Severity: Severity(severity), Line: lineNum % 3},
+		}
+		_ = NewRenderer(WithWidth(width)).Write(io.Discard, annots...)
+		_ = NewRenderer(WithSkipInvalid(), WithMarginNotes(width%100)).Write(io.Discard, annots...)
+		_ = NewRenderer(WithForceColor(), WithSummary(), WithSeverityStacking(),
+			WithSeverityStyles(map[Severity]Style{Severity(severity): Style(style)}),
+			WithSeverityArrowheads(map[Severity]string{Severity(severity): bullet}),
+		).Write(io.Discard, annots...)
+		_ = WriteDiagram(io.Discard, line, annots...)
+		_ = WriteDoc(io.Discard, line+"\n"+line, annots...)
+	})
+}
+
+func TestWrite_invalidFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		annots  []*Annot
+		wantErr error
+	}{
+		{name: "nil annotation", annots: []*Annot{{Col: 0}, nil}, wantErr: &NilAnnotError{}},
+		{name: "huge Col", annots: []*Annot{{Col: MaxCol + 1}}, wantErr: &FieldRangeError{}},
+		{name: "huge ColEnd", annots: []*Annot{{Col: 0, ColEnd: 1 << 40}}, wantErr: &FieldRangeError{}},
+		{name: "negative HangingIndent", annots: []*Annot{{Col: 0, HangingIndent: -1}}, wantErr: &FieldRangeError{}},
+		{name: "huge HangingIndent", annots: []*Annot{{Col: 0, HangingIndent: MaxCol + 1}}, wantErr: &FieldRangeError{}},
+		{name: "escape sequence in Style", annots: []*Annot{{Col: 0, Style: "31m\x1b[2J"}}, wantErr: &StyleError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Write(io.Discard, tt.annots...)
+			if !errors.Is(tt.wantErr, err) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			err = NewRenderer(WithSkipInvalid()).Write(io.Discard, tt.annots...)
+			var skippedErr *SkippedError
+			if !errors.As(err, &skippedErr) || !errors.Is(tt.wantErr, skippedErr.Skipped()[0].Err) {
+				t.Errorf("Write() with skipping error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
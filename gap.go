@@ -0,0 +1,112 @@
+package annot
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+)
+
+// WithGapCompression collapses runs of at least minWidth blank columns
+// between annotations, e.g. of annotations of a long line separated by
+// hundreds of columns. A collapsed run is blank in every row and is
+// replaced by a gap marker in the first row with the number of removed
+// columns:
+//
+//	↑    ↑                             ⋯+49⋯ ↑
+//	│    └─ b                                └─ c
+//	│
+//	└─ a long line that ends far right
+//
+// The columns of the rendered annotations after a gap no longer match the
+// columns of the annotated line.
+func WithGapCompression(minWidth int) Option {
+	return func(r *Renderer) {
+		r.gapMin = minWidth
+	}
+}
+
+// gap is a run of blank columns of all rows of which removed columns are
+// collapsed.
+type gap struct {
+	col, width, removed int
+}
+
+// compressGaps returns the rows with the gaps of at least gapMin columns
+// collapsed to gap markers.
+func (r *Renderer) compressGaps(rows [][]Segment) [][]Segment {
+	var spans [][2]int
+	for _, segments := range rows {
+		for _, s := range segments {
+			spans = append(spans, [2]int{s.Col, s.Col + r.stringWidth(s.Text)})
+		}
+	}
+	slices.SortFunc(spans, func(a, b [2]int) int {
+		return cmp.Compare(a[0], b[0])
+	})
+
+	var gaps []gap
+	end := -1
+	for _, s := range spans {
+		if end != -1 && s[0]-end >= r.gapMin {
+			if removed := r.gapRemoved(s[0] - end); removed > 0 {
+				gaps = append(gaps, gap{end, s[0] - end, removed})
+			}
+		}
+		end = max(end, s[1])
+	}
+	if len(gaps) == 0 {
+		return rows
+	}
+
+	compressed := make([][]Segment, len(rows))
+	for row, segments := range rows {
+		shifted := make([]Segment, 0, len(segments)+len(gaps))
+		for _, s := range segments {
+			s.Col -= removedBefore(gaps, s.Col)
+			shifted = append(shifted, s)
+		}
+		compressed[row] = shifted
+	}
+	for _, g := range gaps {
+		compressed[0] = append(compressed[0], Segment{
+			Col:   g.col + 1 - removedBefore(gaps, g.col),
+			Text:  r.gapMarker(g.removed),
+			Kind:  GapSegment,
+			Index: -1,
+		})
+	}
+	slices.SortStableFunc(compressed[0], func(a, b Segment) int {
+		return cmp.Compare(a.Col, b.Col)
+	})
+	return compressed
+}
+
+// gapRemoved returns the number of removed columns of a gap of width
+// columns, so that its marker fits with a space on both sides. The marker
+// gets one column more if the number has one digit less than assumed.
+func (r *Renderer) gapRemoved(width int) int {
+	for digits, limit := 1, 10; ; digits, limit = digits+1, limit*10 {
+		removed := width - 2 - r.stringWidth(r.gapMarker(0)) + 1 - digits
+		if removed < limit {
+			return removed
+		}
+	}
+}
+
+// gapMarker returns the marker of a gap with removed columns, e.g.
+// "⋯+236⋯".
+func (r *Renderer) gapMarker(removed int) string {
+	return r.glyphs.Gap + "+" + strconv.Itoa(removed) + r.glyphs.Gap
+}
+
+// removedBefore returns the number of columns removed by the gaps that end
+// before or at the column col.
+func removedBefore(gaps []gap, col int) int {
+	removed := 0
+	for _, g := range gaps {
+		if g.col+g.width <= col {
+			removed += g.removed
+		}
+	}
+	return removed
+}
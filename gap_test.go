@@ -0,0 +1,92 @@
+package annot
+
+import "testing"
+
+func TestWithGapCompression(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		annots []*Annot
+		want   string
+	}{
+		{
+			name: "gap between annotations",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"start"}},
+				{Col: 240, Lines: []string{"end"}},
+			},
+			want: `
+↑        ⋯+224⋯ ↑
+└─ start        └─ end
+`,
+		},
+		{
+			name: "stacked annotations",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a long line that ends far right"}},
+				{Col: 5, Lines: []string{"b"}},
+				{Col: 90, Lines: []string{"c"}},
+			},
+			want: `
+↑    ↑                             ⋯+49⋯ ↑
+│    └─ b                                └─ c
+│
+└─ a long line that ends far right
+`,
+		},
+		{
+			name: "gaps shorter than minimum width are kept",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a"}},
+				{Col: 20, Lines: []string{"b"}},
+			},
+			want: `
+↑                   ↑
+└─ a                └─ b
+`,
+		},
+		{
+			name: "several gaps and ranges",
+			annots: []*Annot{
+				{Col: 1, ColEnd: 3, Lines: []string{"a"}},
+				{Col: 60, ColEnd: 64, Lines: []string{"b"}},
+				{Col: 130, Lines: []string{"c"}},
+			},
+			want: `
+ └┬┘   ⋯+47⋯ └─┬─┘  ⋯+57⋯ ↑
+  └─ a         └─ b       └─ c
+`,
+		},
+		{
+			name: "one digit less than assumed",
+			opts: []Option{WithGapCompression(10), WithTheme(ThemeASCII)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a"}},
+				{Col: 21, Lines: []string{"b"}},
+			},
+			want: `
+^     ~+9~  ^
+` + "`" + `-- a       ` + "`" + `-- b
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			if opts == nil {
+				opts = []Option{WithGapCompression(30)}
+			}
+			r := NewRenderer(opts...)
+			if got := "\n" + r.String(tt.annots...); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+			l, err := r.Layout(tt.annots...)
+			if err != nil {
+				t.Fatalf("Layout() unexpected error = %v", err)
+			}
+			if err := l.Check(); err != nil {
+				t.Errorf("Check() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package annot
+
+import "strings"
+
+// ParseGlob returns a field for every wildcard, brace group and character
+// class of a glob pattern like "**/*.{go,md}". The fields are called by
+// what they match, e.g. "any characters except /" for "*" or "one of go,
+// md" for "{go,md}". "**" as a whole path element matches any
+// directories. Brace groups are not supported by path.Match. It returns a
+// *SyntaxError at the column of a malformed part that makes path.Match
+// return path.ErrBadPattern, or of an unclosed brace group.
+func ParseGlob(pattern string) ([]Field, error) {
+	var fields []Field
+	for i := 0; i < len(pattern); {
+		start := i
+		switch pattern[i] {
+		case '\\':
+			if i+1 == len(pattern) {
+				return nil, newSyntaxError(displayCol(pattern, i), "trailing backslash")
+			}
+			i += 2
+			continue
+		case '?':
+			i++
+			fields = append(fields, newField("any character except /", pattern, start, i))
+		case '*':
+			for i < len(pattern) && pattern[i] == '*' {
+				i++
+			}
+			name := "any characters except /"
+			if i-start == 2 && (start == 0 || pattern[start-1] == '/') && (i == len(pattern) || pattern[i] == '/') {
+				name = "any directories"
+			}
+			fields = append(fields, newField(name, pattern, start, i))
+		case '[':
+			end, err := classEnd(pattern, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			name := "one of "
+			ranges := pattern[start+1 : i-1]
+			if rest, ok := strings.CutPrefix(ranges, "^"); ok {
+				name, ranges = "none of ", rest
+			}
+			fields = append(fields, newField(name+ranges, pattern, start, i))
+		case '{':
+			closeIdx := strings.IndexByte(pattern[i:], '}')
+			if closeIdx == -1 {
+				return nil, newSyntaxError(displayCol(pattern, i), "unclosed brace group")
+			}
+			i += closeIdx + 1
+			alternatives := strings.Split(pattern[start+1:i-1], ",")
+			fields = append(fields, newField("one of "+strings.Join(alternatives, ", "), pattern, start, i))
+		default:
+			i++
+		}
+	}
+	return fields, nil
+}
+
+// classEnd returns the index after the character class starting at the
+// "[" at the index start of pattern. It checks the class like path.Match.
+func classEnd(pattern string, start int) (int, error) {
+	i := start + 1
+	if i < len(pattern) && pattern[i] == '^' {
+		i++
+	}
+	for ranges := 0; ; ranges++ {
+		if i == len(pattern) {
+			return 0, newSyntaxError(displayCol(pattern, start), "unclosed character class")
+		}
+		if pattern[i] == ']' {
+			if ranges == 0 {
+				return 0, newSyntaxError(displayCol(pattern, start), "empty character class")
+			}
+			return i + 1, nil
+		}
+		var err error
+		if i, err = classChar(pattern, i); err != nil {
+			return 0, err
+		}
+		if i < len(pattern) && pattern[i] == '-' {
+			if i, err = classChar(pattern, i+1); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+// classChar returns the index after the possibly escaped character at the
+// index i of a character class of pattern.
+func classChar(pattern string, i int) (int, error) {
+	switch {
+	case i == len(pattern):
+		return 0, newSyntaxError(displayCol(pattern, i), "unclosed character class")
+	case pattern[i] == '-' || pattern[i] == ']':
+		return 0, newSyntaxError(displayCol(pattern, i), "missing character of range")
+	case pattern[i] == '\\':
+		i++
+		if i == len(pattern) {
+			return 0, newSyntaxError(displayCol(pattern, i-1), "trailing backslash")
+		}
+	}
+	for i++; i < len(pattern) && pattern[i]&0xC0 == 0x80; i++ {
+		// Skip continuation bytes of UTF-8 encoded characters.
+	}
+	return i, nil
+}
@@ -0,0 +1,92 @@
+package annot
+
+import (
+	"path"
+	"testing"
+)
+
+func TestParseGlob(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name:    "wildcards and brace group",
+			pattern: "**/*.{go,md}",
+			wantW: `
+**/*.{go,md}
+├┘ ↑ └──┬──┘
+│  │    └─ one of go, md
+│  │
+│  └─ any characters except /
+│
+└─ any directories
+`,
+		},
+		{
+			name:    "character classes and escape",
+			pattern: `f?[^0-9]\*[ab]`,
+			wantW: `
+f?[^0-9]\*[ab]
+ ↑└─┬──┘  └┬─┘
+ │  │      └─ one of ab
+ │  │
+ │  └─ none of 0-9
+ │
+ └─ any character except /
+`,
+		},
+		{
+			name:    "unclosed character class",
+			pattern: "*.[ch",
+			wantW: `
+*.[ch
+  ↑
+  └─ unclosed character class
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "missing end of range",
+			pattern: "[a-]",
+			wantW: `
+[a-]
+   ↑
+   └─ missing character of range
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "empty character class",
+			pattern: "[]",
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "trailing backslash",
+			pattern: `a\`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "unclosed brace group",
+			pattern: "*.{go",
+			wantErr: &SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseGlob(tt.pattern)
+			if _, matchErr := path.Match(tt.pattern, ""); matchErr != nil && err == nil {
+				t.Errorf("ParseGlob() error = nil, path.Match() error = %v", matchErr)
+			}
+			annots, ok := fieldsOrSyntaxErr(t, err, tt.wantErr, fields...)
+			if !ok || tt.wantW == "" {
+				return
+			}
+			if gotW := "\n" + tt.pattern + "\n" + String(annots...); gotW != tt.wantW {
+				t.Errorf("ParseGlob() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
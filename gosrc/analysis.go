@@ -0,0 +1,134 @@
+package gosrc
+
+import (
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/meyermarcel/annot"
+)
+
+// TextEdit is a replacement of the text from Pos to the exclusive End with
+// NewText. It has the fields of analysis.TextEdit of
+// golang.org/x/tools/go/analysis, so a text edit e of a suggested fix is
+// converted with gosrc.TextEdit(e).
+type TextEdit struct {
+	Pos     token.Pos
+	End     token.Pos
+	NewText []byte
+}
+
+// SuggestedFix is a fix of a Finding with the fields of
+// analysis.SuggestedFix.
+type SuggestedFix struct {
+	Message   string
+	TextEdits []TextEdit
+}
+
+// Finding is a diagnostic of an analyzer with the fields of
+// analysis.Diagnostic that AnalysisDiagnostic converts.
+type Finding struct {
+	Pos            token.Pos
+	End            token.Pos
+	Category       string
+	Message        string
+	SuggestedFixes []SuggestedFix
+}
+
+// AnalysisDiagnostic returns a diagnostic with severity of the finding f
+// of an analyzer of go vet or golang.org/x/tools/go/analysis. gosrc does
+// not import golang.org/x/tools on purpose, so annot keeps its single
+// dependency, and a diagnostic d of an analyzer is copied into a Finding:
+//
+//	f := gosrc.Finding{Pos: d.Pos, End: d.End, Category: d.Category, Message: d.Message}
+//	for _, sf := range d.SuggestedFixes {
+//		fix := gosrc.SuggestedFix{Message: sf.Message}
+//		for _, e := range sf.TextEdits {
+//			fix.TextEdits = append(fix.TextEdits, gosrc.TextEdit(e))
+//		}
+//		f.SuggestedFixes = append(f.SuggestedFixes, fix)
+//	}
+//	ad, err := gosrc.AnalysisDiagnostic(pass.Fset, nil, f, annot.SeverityWarning)
+//
+// The range from f.Pos to the exclusive f.End is annotated like RangeAnnot
+// and an End of token.NoPos annotates an arrow at Pos. A category is
+// appended to the message in parentheses. The text edits of the line of
+// Pos are fixes of the diagnostic, which annot.WriteReport shows as the
+// line with the fixes applied. Text edits of other lines or with line
+// breaks are not shown. Source, the columns and the fixes keep the tabs of
+// the line like in Diagnostic. If src is nil, the file of Pos is read.
+func AnalysisDiagnostic(fset *token.FileSet, src []byte, f Finding, severity annot.Severity) (*annot.Diagnostic, error) {
+	pos, end := f.Pos, f.End
+	if src == nil {
+		file := fset.File(pos)
+		if file == nil {
+			return nil, newPosError(pos)
+		}
+		var err error
+		if src, err = os.ReadFile(file.Name()); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := lineOf(fset, src, pos)
+	if err != nil {
+		return nil, err
+	}
+	a := &annot.Annot{Col: sourceCol(l.text, l.col)}
+	if end.IsValid() {
+		if a, err = spanAnnot(l, pos, end, sourceCol); err != nil {
+			return nil, err
+		}
+	}
+
+	message := f.Message
+	if f.Category != "" {
+		message += " (" + f.Category + ")"
+	}
+	p := fset.Position(pos)
+	d := &annot.Diagnostic{
+		File:     p.Filename,
+		Line:     p.Line,
+		Source:   l.text,
+		Severity: severity,
+		Message:  message,
+		Annots:   []*annot.Annot{a},
+	}
+	for _, sf := range f.SuggestedFixes {
+		for _, e := range sf.TextEdits {
+			if fix, ok := lineFix(fset, src, p, e); ok {
+				d.Fixes = append(d.Fixes, fix)
+			}
+		}
+	}
+	return d, nil
+}
+
+// lineFix returns the fix of the text edit e in the columns of the source
+// line if e is within the line of the position p and does not insert line
+// breaks.
+func lineFix(fset *token.FileSet, src []byte, p token.Position, e TextEdit) (annot.Fix, bool) {
+	end := e.End
+	if !end.IsValid() {
+		end = e.Pos
+	}
+	if !sameLine(fset.Position(e.Pos), p) || !sameLine(fset.Position(end), p) || end < e.Pos ||
+		strings.ContainsAny(string(e.NewText), "\r\n") {
+		return annot.Fix{}, false
+	}
+	l, err := lineOf(fset, src, e.Pos)
+	if err != nil {
+		return annot.Fix{}, false
+	}
+	col := sourceCol(l.text, l.col)
+	return annot.Fix{
+		Col:         col,
+		Width:       sourceCol(l.text, l.col+int(end-e.Pos)) - col,
+		Replacement: string(e.NewText),
+	}, true
+}
+
+// sameLine reports whether p and q are in the same line of the same file.
+func sameLine(p, q token.Position) bool {
+	return p.Filename == q.Filename && p.Line == q.Line
+}
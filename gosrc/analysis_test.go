@@ -0,0 +1,145 @@
+package gosrc
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/meyermarcel/annot"
+)
+
+func TestAnalysisDiagnostic(t *testing.T) {
+	fset, f := parse(t)
+	call := find(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		return ok && call.Fun.(*ast.Ident).Name == "println"
+	}).(*ast.CallExpr)
+	ifStmt := find(f, func(n ast.Node) bool {
+		_, ok := n.(*ast.IfStmt)
+		return ok
+	})
+
+	tests := []struct {
+		name     string
+		end      token.Pos
+		category string
+		fixes    []SuggestedFix
+		severity annot.Severity
+		wantW    string
+	}{
+		{
+			name: "range with fixes",
+			end:  call.End(),
+			fixes: []SuggestedFix{
+				{
+					Message: "use fmt.Println",
+					TextEdits: []TextEdit{
+						{Pos: call.Fun.Pos(), End: call.Fun.End(), NewText: []byte("fmt.Println")},
+						{Pos: call.Fun.Pos(), NewText: []byte("//")},
+					},
+				},
+				{
+					Message: "other lines",
+					TextEdits: []TextEdit{
+						{Pos: ifStmt.Pos(), End: ifStmt.Pos(), NewText: []byte("x")},
+						{Pos: call.End(), NewText: []byte("\n")},
+					},
+				},
+			},
+			severity: annot.SeverityWarning,
+			wantW: `
+p.go: 1 warning
+
+warning: use fmt
+4 │         grüße := "世界"; println(grüße)
+  │                          └─────┬──────┘
+  │                                └─ 
+  -         grüße := "世界"; println(grüße)
+  +         grüße := "世界"; //fmt.Println(grüße)
+
+1 problem (1 warning)
+`,
+		},
+		{
+			name:     "arrow with category",
+			end:      token.NoPos,
+			category: "println",
+			severity: annot.SeverityError,
+			wantW: `
+p.go: 1 error
+
+error: use fmt (println)
+4 │         grüße := "世界"; println(grüße)
+  │                          ↑
+  │                          └─ 
+
+1 problem (1 error)
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := Finding{Pos: call.Pos(), End: tt.end, Category: tt.category, Message: "use fmt", SuggestedFixes: tt.fixes}
+			d, err := AnalysisDiagnostic(fset, []byte(src), finding, tt.severity)
+			if err != nil {
+				t.Fatalf("AnalysisDiagnostic() unexpected error = %v", err)
+			}
+			w := &bytes.Buffer{}
+			if err := annot.WriteReport(w, d); err != nil {
+				t.Fatalf("WriteReport() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("AnalysisDiagnostic() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestAnalysisDiagnostic_readFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "p.go")
+	if err := os.WriteFile(name, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finding := Finding{Pos: f.Name.Pos(), End: f.Name.End(), Message: "package name"}
+	d, err := AnalysisDiagnostic(fset, nil, finding, annot.SeverityWarning)
+	if err != nil {
+		t.Fatalf("AnalysisDiagnostic() unexpected error = %v", err)
+	}
+	if d.File != name || d.Line != 1 || d.Source != "package p" || d.Annots[0].Col != 8 {
+		t.Errorf("AnalysisDiagnostic() = %+v, want diagnostic of package name", d)
+	}
+}
+
+func TestAnalysisDiagnostic_tabs(t *testing.T) {
+	fset, f := parse(t)
+	call := find(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		return ok && call.Fun.(*ast.Ident).Name == "println"
+	}).(*ast.CallExpr)
+	edit := TextEdit{Pos: call.Fun.Pos(), End: call.Fun.End(), NewText: []byte("fmt.Println")}
+	finding := Finding{Pos: call.Pos(), End: call.End(), Message: "use fmt", SuggestedFixes: []SuggestedFix{{TextEdits: []TextEdit{edit}}}}
+	d, err := AnalysisDiagnostic(fset, []byte(src), finding, annot.SeverityWarning)
+	if err != nil {
+		t.Fatalf("AnalysisDiagnostic() unexpected error = %v", err)
+	}
+	if d.Source != "\tgrüße := \"世界\"; println(grüße)" || d.Annots[0].Col != 18 || d.Fixes[0].Col != 18 {
+		t.Errorf("AnalysisDiagnostic() = %+v, want source with tab and column 18", d)
+	}
+	w := &bytes.Buffer{}
+	if err := annot.WriteGitHubActions(w, d); err != nil {
+		t.Fatalf("WriteGitHubActions() unexpected error = %v", err)
+	}
+	want := "::warning file=p.go,line=4,col=17,endColumn=30::use fmt\n"
+	if got := w.String(); got != want {
+		t.Errorf("WriteGitHubActions() got = %q, want %q", got, want)
+	}
+}
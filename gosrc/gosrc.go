@@ -0,0 +1,187 @@
+// Package gosrc converts positions of go/token and spans of go/ast nodes
+// into annotations of the lines of Go source files.
+//
+// Columns of go/token count bytes, but annotations need display columns.
+// The functions of gosrc return the source line with expanded tabs and
+// annotations with the display columns of that line, so tabs and
+// multi-byte characters like "ä" or "世" are annotated correctly:
+//
+//	line, _ := gosrc.Line(fset, src, call.Pos())
+//	a, _ := gosrc.NodeAnnot(fset, src, call, "result is not used")
+//	fmt.Println(line)
+//	_ = annot.Write(os.Stdout, a)
+//
+// Diagnostic and AnalysisDiagnostic return diagnostics for
+// annot.WriteReport and the exporters of annot instead. Like the
+// diagnostics of annot.ParseCompilerOutput, they keep the tabs of the
+// source line and count a tab as one column, so exporters get the columns
+// of the original line. AnalysisDiagnostic converts diagnostics of
+// analyzers of go vet with their suggested fixes.
+package gosrc
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/meyermarcel/annot"
+)
+
+// tabWidth is the distance of tab stops like in the source lines of
+// annot.ParseCompilerOutput.
+const tabWidth = 8
+
+// Line returns the line of pos in src with expanded tabs. The source src
+// is the content of the file of pos in fset. It returns a *PosError if pos
+// is not a position of fset or src is shorter than the file.
+func Line(fset *token.FileSet, src []byte, pos token.Pos) (string, error) {
+	l, err := lineOf(fset, src, pos)
+	if err != nil {
+		return "", err
+	}
+	return expandTabs(l.text), nil
+}
+
+// Annot returns an arrow annotation with lines at the display column of
+// pos in the line returned by Line.
+func Annot(fset *token.FileSet, src []byte, pos token.Pos, lines ...string) (*annot.Annot, error) {
+	l, err := lineOf(fset, src, pos)
+	if err != nil {
+		return nil, err
+	}
+	return &annot.Annot{Col: displayCol(l.text, l.col), Lines: lines}, nil
+}
+
+// RangeAnnot returns an annotation with lines of the range from pos to
+// the exclusive position end, e.g. the End of a node, in the line returned
+// by Line. A range that continues in the following lines is cut at the end
+// of the line of pos. If the range covers only one column, an arrow is
+// annotated.
+func RangeAnnot(fset *token.FileSet, src []byte, pos, end token.Pos, lines ...string) (*annot.Annot, error) {
+	l, err := lineOf(fset, src, pos)
+	if err != nil {
+		return nil, err
+	}
+	a, err := spanAnnot(l, pos, end, displayCol)
+	if err != nil {
+		return nil, err
+	}
+	a.Lines = lines
+	return a, nil
+}
+
+// spanAnnot returns an annotation of the range from pos in l to the
+// exclusive position end with the columns colOf returns for byte columns.
+func spanAnnot(l line, pos, end token.Pos, colOf func(text string, col int) int) (*annot.Annot, error) {
+	if !end.IsValid() || end < pos {
+		return nil, newPosError(end)
+	}
+	endCol := min(l.col+int(end-pos), len(l.text))
+	a := &annot.Annot{Col: colOf(l.text, l.col)}
+	if colEnd := colOf(l.text, endCol) - 1; colEnd > a.Col {
+		a.ColEnd = colEnd
+	}
+	return a, nil
+}
+
+// NodeAnnot returns an annotation with lines of the span of node like
+// RangeAnnot.
+func NodeAnnot(fset *token.FileSet, src []byte, node ast.Node, lines ...string) (*annot.Annot, error) {
+	return RangeAnnot(fset, src, node.Pos(), node.End(), lines...)
+}
+
+// Diagnostic returns a diagnostic of message about the line of the span
+// of node with an annotation of the span like NodeAnnot, e.g. for a
+// finding of a linter. Source is the line with its tabs and the columns
+// count a tab as one column. Use annot.WriteReport to write diagnostics.
+func Diagnostic(fset *token.FileSet, src []byte, node ast.Node, severity annot.Severity, message string) (*annot.Diagnostic, error) {
+	l, err := lineOf(fset, src, node.Pos())
+	if err != nil {
+		return nil, err
+	}
+	a, err := spanAnnot(l, node.Pos(), node.End(), sourceCol)
+	if err != nil {
+		return nil, err
+	}
+	p := fset.Position(node.Pos())
+	return &annot.Diagnostic{
+		File:     p.Filename,
+		Line:     p.Line,
+		Source:   l.text,
+		Severity: severity,
+		Message:  message,
+		Annots:   []*annot.Annot{a},
+	}, nil
+}
+
+// line is the line of a position with the 0-based byte column of the
+// position in text.
+type line struct {
+	text string
+	col  int
+}
+
+func lineOf(fset *token.FileSet, src []byte, pos token.Pos) (line, error) {
+	f := fset.File(pos)
+	if f == nil || len(src) < f.Size() {
+		return line{}, newPosError(pos)
+	}
+	offset := f.Offset(pos)
+	start := f.Offset(f.LineStart(f.Line(pos)))
+	end := len(src)
+	if i := strings.IndexByte(string(src[start:]), '\n'); i != -1 {
+		end = start + i
+	}
+	return line{text: strings.TrimSuffix(string(src[start:end]), "\r"), col: offset - start}, nil
+}
+
+// displayCol returns the display column of the 0-based byte column col
+// of text with expanded tabs.
+func displayCol(text string, col int) int {
+	return annot.DisplayWidth(expandTabs(text[:min(col, len(text))]))
+}
+
+// sourceCol returns the column of the 0-based byte column col of text in
+// which a tab counts as one column.
+func sourceCol(text string, col int) int {
+	return annot.DisplayWidth(strings.ReplaceAll(text[:min(col, len(text))], "\t", " "))
+}
+
+// expandTabs replaces the tabs of s with spaces up to the next tab stop.
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	b := &strings.Builder{}
+	width := 0
+	for _, part := range strings.SplitAfter(s, "\t") {
+		text, isTab := strings.CutSuffix(part, "\t")
+		b.WriteString(text)
+		width += annot.DisplayWidth(text)
+		if isTab {
+			spaces := tabWidth - width%tabWidth
+			b.WriteString(strings.Repeat(" ", spaces))
+			width += spaces
+		}
+	}
+	return b.String()
+}
+
+type PosError struct {
+	pos token.Pos
+}
+
+func newPosError(pos token.Pos) *PosError {
+	return &PosError{pos}
+}
+
+func (e *PosError) Error() string {
+	return fmt.Sprintf("gosrc: position %d is not in a file of the file set and its source", e.pos)
+}
+
+func (e *PosError) Is(target error) bool {
+	var posError *PosError
+	return errors.As(target, &posError)
+}
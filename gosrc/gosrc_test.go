@@ -0,0 +1,201 @@
+package gosrc
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/meyermarcel/annot"
+)
+
+const src = "package p\n\nfunc f() {\n\tgrüße := \"世界\"; println(grüße)\n\tif x := g(1,\n\t\t2); x {\n\t}\n}\n"
+
+func parse(t *testing.T) (*token.FileSet, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fset, f
+}
+
+// find returns the first node of f for which match returns true.
+func find(f *ast.File, match func(ast.Node) bool) ast.Node {
+	var found ast.Node
+	ast.Inspect(f, func(n ast.Node) bool {
+		if found == nil && n != nil && match(n) {
+			found = n
+		}
+		return found == nil
+	})
+	return found
+}
+
+func TestNodeAnnot(t *testing.T) {
+	fset, f := parse(t)
+	tests := []struct {
+		name  string
+		match func(ast.Node) bool
+		wantW string
+	}{
+		{
+			name: "tab and multi-byte characters",
+			match: func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				return ok && call.Fun.(*ast.Ident).Name == "println"
+			},
+			wantW: `
+        grüße := "世界"; println(grüße)
+                         └─────┬──────┘
+                               └─ x
+`,
+		},
+		{
+			name: "wide characters",
+			match: func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+				return ok && lit.Kind == token.STRING
+			},
+			wantW: `
+        grüße := "世界"; println(grüße)
+                 └─┬──┘
+                   └─ x
+`,
+		},
+		{
+			name: "range cut at end of line",
+			match: func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				return ok && call.Fun.(*ast.Ident).Name == "g"
+			},
+			wantW: `
+        if x := g(1,
+                └┬─┘
+                 └─ x
+`,
+		},
+		{
+			name: "one column",
+			match: func(n ast.Node) bool {
+				id, ok := n.(*ast.Ident)
+				return ok && id.Name == "x"
+			},
+			wantW: `
+        if x := g(1,
+           ↑
+           └─ x
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := find(f, tt.match)
+			line, err := Line(fset, []byte(src), n.Pos())
+			if err != nil {
+				t.Fatalf("Line() unexpected error = %v", err)
+			}
+			a, err := NodeAnnot(fset, []byte(src), n, "x")
+			if err != nil {
+				t.Fatalf("NodeAnnot() unexpected error = %v", err)
+			}
+			w := &bytes.Buffer{}
+			if err := annot.WriteLine(w, line, a); err != nil {
+				t.Fatalf("WriteLine() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("NodeAnnot() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestAnnot(t *testing.T) {
+	fset, f := parse(t)
+	n := find(f, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		return ok && id.Name == "println"
+	})
+	a, err := Annot(fset, []byte(src), n.Pos(), "builtin")
+	if err != nil {
+		t.Fatalf("Annot() unexpected error = %v", err)
+	}
+	if a.Col != 25 || a.ColEnd != 0 || len(a.Lines) != 1 {
+		t.Errorf("Annot() = %+v, want arrow at column 25", a)
+	}
+}
+
+func TestDiagnostic(t *testing.T) {
+	fset, f := parse(t)
+	n := find(f, func(n ast.Node) bool {
+		_, ok := n.(*ast.IfStmt)
+		return ok
+	})
+	d, err := Diagnostic(fset, []byte(src), n, annot.SeverityWarning, "simplify")
+	if err != nil {
+		t.Fatalf("Diagnostic() unexpected error = %v", err)
+	}
+	w := &bytes.Buffer{}
+	if err := annot.WriteReport(w, d); err != nil {
+		t.Fatalf("WriteReport() unexpected error = %v", err)
+	}
+	want := `
+p.go: 1 warning
+
+warning: simplify
+5 │         if x := g(1,
+  │         └────┬─────┘
+  │              └─ 
+
+1 problem (1 warning)
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("Diagnostic() gotW = %v, want %v", gotW, want)
+	}
+}
+
+func TestDiagnostic_tabs(t *testing.T) {
+	fset, f := parse(t)
+	n := find(f, func(n ast.Node) bool {
+		_, ok := n.(*ast.IfStmt)
+		return ok
+	})
+	d, err := Diagnostic(fset, []byte(src), n, annot.SeverityWarning, "simplify")
+	if err != nil {
+		t.Fatalf("Diagnostic() unexpected error = %v", err)
+	}
+	if d.Source != "\tif x := g(1," || d.Annots[0].Col != 1 || d.Annots[0].ColEnd != 12 {
+		t.Errorf("Diagnostic() = %+v, want source with tab and columns 1 to 12", d)
+	}
+	w := &bytes.Buffer{}
+	if err := annot.WriteGitHubActions(w, d); err != nil {
+		t.Fatalf("WriteGitHubActions() unexpected error = %v", err)
+	}
+	want := "::warning file=p.go,line=5,col=2,endColumn=13::simplify\n"
+	if got := w.String(); got != want {
+		t.Errorf("WriteGitHubActions() got = %q, want %q", got, want)
+	}
+}
+
+func TestPosError(t *testing.T) {
+	fset, f := parse(t)
+	tests := []struct {
+		name     string
+		src      string
+		pos, end token.Pos
+	}{
+		{name: "no position", src: src, pos: token.NoPos, end: f.End()},
+		{name: "short source", src: "package p", pos: f.Package, end: f.End()},
+		{name: "end before pos", src: src, pos: f.End(), end: f.Package},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RangeAnnot(fset, []byte(tt.src), tt.pos, tt.end); !errors.Is(&PosError{}, err) {
+				t.Errorf("RangeAnnot() error = %v, want %v", err, &PosError{})
+			}
+		})
+	}
+}
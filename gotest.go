@@ -0,0 +1,107 @@
+package annot
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// goTestName matches lines naming the test of the following output.
+	goTestName = regexp.MustCompile(`^(?:=== (?:RUN|CONT|NAME)\s+|\s*--- FAIL: )(\S+)`)
+
+	// goTestLocation matches failure locations like "    a_test.go:9: msg".
+	goTestLocation = regexp.MustCompile(`^(\s+)(\S+\.go):(\d+): (.*)$`)
+)
+
+// ParseGoTestOutput parses the failure locations in the output of go test,
+// e.g.
+//
+//	--- FAIL: TestAdd (0.00s)
+//	    add_test.go:9: got 3, want 4
+//
+// Every location becomes an error diagnostic with the name of the failed
+// test as message. The source lines are read from fsys, which is usually
+// the directory of the tested package. A diagnostic with a source line
+// gets a range annotation of the line without its indentation with the
+// failure message as lines. Source and the columns keep the tabs of the
+// line like in ParseCompilerOutput. If fsys is nil or a file cannot be
+// read, the failure message is appended to the message of the diagnostic
+// instead.
+func ParseGoTestOutput(r io.Reader, fsys fs.FS) ([]*Diagnostic, error) {
+	sources := newSourceCache(fsys)
+
+	var diags []*Diagnostic
+	var failure []string
+	indent := ""
+	test := ""
+
+	flush := func() {
+		if len(diags) == 0 || failure == nil {
+			return
+		}
+		d := diags[len(diags)-1]
+		if len(d.Annots) != 0 {
+			d.Annots[0].Lines = failure
+		} else {
+			d.Message = strings.Join(append([]string{d.Message}, failure...), "\n")
+		}
+		failure = nil
+	}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		text := strings.TrimSuffix(s.Text(), "\r")
+
+		if failure != nil && strings.HasPrefix(text, indent+" ") {
+			failure = append(failure, strings.TrimSpace(text))
+			continue
+		}
+		flush()
+
+		if m := goTestName.FindStringSubmatch(text); m != nil {
+			test = m[1]
+			continue
+		}
+
+		m := goTestLocation.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(m[3])
+		d := &Diagnostic{File: m[2], Line: lineNum, Severity: SeverityError, Message: test}
+		if source, ok := sources.line(d.File, d.Line); ok {
+			d.Source = source
+			if a := lineAnnot(source); a != nil {
+				d.Annots = []*Annot{a}
+			}
+		}
+		diags = append(diags, d)
+		indent = m[1]
+		failure = []string{m[4]}
+	}
+	flush()
+	return diags, s.Err()
+}
+
+// lineAnnot returns an annotation of source without its leading and
+// trailing white space or nil if source is blank. The columns are the
+// columns of source in which a tab counts as one column.
+func lineAnnot(source string) *Annot {
+	trimmed := strings.TrimLeftFunc(source, unicode.IsSpace)
+	content := strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	if content == "" {
+		return nil
+	}
+	col := byteColToSourceCol(source, len(source)-len(trimmed))
+	colEnd := byteColToSourceCol(source, len(source)-len(trimmed)+len(content)) - 1
+	a := &Annot{Col: col}
+	if colEnd > col {
+		a.ColEnd = colEnd
+	}
+	return a
+}
@@ -0,0 +1,125 @@
+package annot
+
+import (
+	"bytes"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseGoTestOutput(t *testing.T) {
+	fsys := fstest.MapFS{
+		"add_test.go": {Data: []byte("package add\n\nfunc TestAdd(t *testing.T) {\n\tif got := Add(1, 2); got != 4 {\n\t\tt.Errorf(\"got %v, want 4\", got)\n\n")},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		fsys   fs.FS
+		want   []*Diagnostic
+	}{
+		{
+			name:   "passed",
+			output: "ok  \texample\t0.001s\n",
+			fsys:   fsys,
+			want:   nil,
+		},
+		{
+			name: "failed test",
+			output: `--- FAIL: TestAdd (0.00s)
+    add_test.go:5: got 3, want 4
+        second line
+FAIL
+FAIL	example	0.001s
+`,
+			fsys: fsys,
+			want: []*Diagnostic{
+				{
+					File: "add_test.go", Line: 5, Source: "\t\tt.Errorf(\"got %v, want 4\", got)",
+					Severity: SeverityError, Message: "TestAdd",
+					Annots: []*Annot{{Col: 2, ColEnd: 32, Lines: []string{"got 3, want 4", "second line"}}},
+				},
+			},
+		},
+		{
+			name: "verbose subtests",
+			output: `=== RUN   TestAdd
+=== RUN   TestAdd/one
+    add_test.go:4: first
+=== RUN   TestAdd/two
+    add_test.go:6: blank line
+--- FAIL: TestAdd (0.00s)
+    --- FAIL: TestAdd/one (0.00s)
+    --- FAIL: TestAdd/two (0.00s)
+`,
+			fsys: fsys,
+			want: []*Diagnostic{
+				{
+					File: "add_test.go", Line: 4, Source: "\tif got := Add(1, 2); got != 4 {",
+					Severity: SeverityError, Message: "TestAdd/one",
+					Annots: []*Annot{{Col: 1, ColEnd: 31, Lines: []string{"first"}}},
+				},
+				{
+					File: "add_test.go", Line: 6, Source: "",
+					Severity: SeverityError, Message: "TestAdd/two\nblank line",
+				},
+			},
+		},
+		{
+			name:   "without file system",
+			output: "--- FAIL: TestAdd (0.00s)\n    add_test.go:5: got 3\n        more\n",
+			fsys:   nil,
+			want: []*Diagnostic{
+				{File: "add_test.go", Line: 5, Severity: SeverityError, Message: "TestAdd\ngot 3\nmore"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGoTestOutput(strings.NewReader(tt.output), tt.fsys)
+			if err != nil {
+				t.Fatalf("ParseGoTestOutput() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGoTestOutput() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoTestOutput_tabs(t *testing.T) {
+	fsys := fstest.MapFS{"a_test.go": {Data: []byte("package a\n\tt.Fail()\n")}}
+	diags, err := ParseGoTestOutput(strings.NewReader("--- FAIL: TestA (0.00s)\n    a_test.go:2: failed\n"), fsys)
+	if err != nil {
+		t.Fatalf("ParseGoTestOutput() unexpected error = %v", err)
+	}
+
+	w := &bytes.Buffer{}
+	if err := WriteGitHubActions(w, diags...); err != nil {
+		t.Fatalf("WriteGitHubActions() unexpected error = %v", err)
+	}
+	wantActions := "::error file=a_test.go,line=2,col=2,endColumn=9::TestA%0Afailed\n"
+	if got := w.String(); got != wantActions {
+		t.Errorf("WriteGitHubActions() got = %q, want %q", got, wantActions)
+	}
+
+	w.Reset()
+	if err := WriteReport(w, diags...); err != nil {
+		t.Fatalf("WriteReport() unexpected error = %v", err)
+	}
+	want := `
+a_test.go: 1 error
+
+error: TestA
+2 │         t.Fail()
+  │         └──┬───┘
+  │            └─ failed
+
+1 problem (1 error)
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("WriteReport() gotW = %v, want %v", gotW, want)
+	}
+}
@@ -0,0 +1,93 @@
+package annot
+
+import "strings"
+
+// WithGrid renders the lines of the annotations side by side in cells of
+// cellWidth columns, e.g. for records of fixed-width fields. The lines of
+// the leftmost annotation are rendered in the first cell, the lines of the
+// next annotation in the second cell and so on. Horizontal runs connect
+// the stems with the cells, e.g. "└───┐". Lines are wrapped to fit a cell
+// and all cells are widened if a word does not fit. WithGrid takes
+// precedence over WithMarginNotes.
+func WithGrid(cellWidth int) Option {
+	return func(r *Renderer) {
+		r.gridCell = cellWidth
+	}
+}
+
+// grid reports whether lines are rendered in cells.
+func (r *Renderer) grid() bool {
+	return r.gridCell > 0
+}
+
+// gridRows returns the rendered rows of annotations with their lines in
+// cells. Runs to the left turn first from the leftmost to the rightmost
+// annotation, then runs to the right from the rightmost to the leftmost
+// annotation, so that runs never cross stems.
+func (r *Renderer) gridRows(annots []*Annot) [][]Segment {
+	cellWidth := r.gridCell
+	for _, a := range annots {
+		for _, l := range a.lines {
+			// Connector, line and at least one space before the next cell.
+			cellWidth = max(cellWidth, r.connWidth+l.length+1)
+		}
+	}
+
+	turns := make([]int, len(annots))
+	textRow := 0
+	for aIdx, a := range annots {
+		turns[aIdx] = -1
+		if aIdx*cellWidth < a.pipeColIdx {
+			turns[aIdx] = textRow
+			textRow++
+		}
+	}
+	for aIdxDecr := len(annots) - 1; 0 <= aIdxDecr; aIdxDecr-- {
+		if aIdxDecr*cellWidth > annots[aIdxDecr].pipeColIdx {
+			turns[aIdxDecr] = textRow
+			textRow++
+		}
+	}
+
+	rowCount := 0
+	for _, a := range annots {
+		a.row = textRow
+		rowCount = max(rowCount, a.row+len(a.lines))
+	}
+
+	rows := make([][]Segment, rowCount+1)
+	rows[0] = r.markerRow(annots)
+
+	for row := 0; row < rowCount; row++ {
+		var segments []Segment
+		for aIdx, a := range annots {
+			cellCol := aIdx * cellWidth
+			switch {
+			case row < a.row && (turns[aIdx] < 0 || row < turns[aIdx]):
+				segments = append(segments, Segment{Col: a.pipeColIdx, Text: r.pipe(a), Kind: PipeSegment, Annot: a, Index: a.idx})
+			case row == turns[aIdx]:
+				col, run := r.gridRun(a.pipeColIdx, cellCol)
+				segments = append(segments, Segment{Col: col, Text: run, Kind: ConnectorSegment, Annot: a, Index: a.idx})
+			case row < a.row:
+				segments = append(segments, Segment{Col: cellCol, Text: r.pipe(a), Kind: PipeSegment, Annot: a, Index: a.idx})
+			case row == a.row:
+				segments = append(segments, Segment{Col: cellCol, Text: r.connector, Kind: ConnectorSegment, Annot: a, Index: a.idx})
+				segments = appendLine(segments, cellCol+r.connWidth, a.lines[0], a)
+			case row < a.row+len(a.lines):
+				segments = appendLine(segments, cellCol+r.connWidth, a.lines[row-a.row], a)
+			}
+		}
+		rows[row+1] = segments
+	}
+
+	return rows
+}
+
+// gridRun returns the start column and the drawn run from the stem in
+// pipeCol to a cell starting at cellCol, e.g. "└───┐" or "┌───┘".
+func (r *Renderer) gridRun(pipeCol, cellCol int) (int, string) {
+	if pipeCol < cellCol {
+		return pipeCol, r.glyphs.Corner + strings.Repeat(r.glyphs.Horizontal, cellCol-pipeCol-1) + r.glyphs.GridRight
+	}
+	return cellCol, r.glyphs.GridLeft + strings.Repeat(r.glyphs.Horizontal, pipeCol-cellCol-1) + r.glyphs.RangeRight
+}
@@ -0,0 +1,63 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithGrid(t *testing.T) {
+	tests := []struct {
+		name      string
+		cellWidth int
+		annots    []*Annot
+		wantW     string
+	}{
+		{
+			name:      "runs to the left and right",
+			cellWidth: 10,
+			annots: []*Annot{
+				{Col: 2, ColEnd: 5, Lines: []string{"id"}},
+				{Col: 8, Lines: []string{"straight"}},
+				{Col: 12, ColEnd: 17, Lines: []string{"date"}},
+				{Col: 31, Lines: []string{"flag"}},
+			},
+			wantW: `
+  └┬─┘  ↑   └─┬──┘             ↑
+┌──┘    │     │                │
+│       │     │                └────┐
+│       │     └─────────┐           │
+│       └───┐           │           │
+└─ id       └─ straight └─ date     └─ flag
+`,
+		},
+		{
+			name:      "wrapped lines",
+			cellWidth: 12,
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a long line"}},
+				{Col: 12, Lines: []string{"x"}},
+			},
+			wantW: `
+↑           ↑
+└─ a long   └─ x
+   line
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(WithGrid(tt.cellWidth))
+			w := &bytes.Buffer{}
+			if err := r.Write(w, tt.annots...); err != nil {
+				t.Fatalf("Write() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			l, _ := r.Layout(tt.annots...)
+			if err := l.Check(); err != nil {
+				t.Errorf("Check() unexpected error = %v", err)
+			}
+		})
+	}
+}
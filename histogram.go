@@ -0,0 +1,105 @@
+package annot
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// bars are the bar characters of a histogram from the lowest to the
+// highest count.
+const bars = "▁▂▃▄▅▆▇█"
+
+// Histogram is a row of bars, one bar per bucket, e.g. of a latency
+// distribution. Annotations of buckets point at their bars.
+//
+//	▁▂▅█▆▃▂▁  ▁
+//	   ↑      ↑
+//	   │      └─ p99 here
+//	   └─ mode
+type Histogram struct {
+	// Counts are the counts of the buckets.
+	Counts []float64
+
+	// BucketWidth is the number of columns of a bar. A BucketWidth of 0
+	// or less draws bars one column wide.
+	BucketWidth int
+}
+
+// String returns the bars of the buckets. The height of a bar is relative
+// to the highest count. Buckets with a count of 0 or less are blank.
+func (h Histogram) String() string {
+	highest := 0.0
+	for _, c := range h.Counts {
+		highest = max(highest, c)
+	}
+
+	levels := []rune(bars)
+	b := &strings.Builder{}
+	for _, c := range h.Counts {
+		bar := " "
+		if c > 0 {
+			level := int(math.Ceil(c/highest*float64(len(levels)))) - 1
+			bar = string(levels[min(max(level, 0), len(levels)-1)])
+		}
+		b.WriteString(strings.Repeat(bar, h.bucketWidth()))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Annot returns an annotation with lines for the bar of bucket.
+func (h Histogram) Annot(bucket int, lines ...string) *Annot {
+	return h.AnnotRange(bucket, bucket, lines...)
+}
+
+// AnnotRange returns an annotation with lines for the bars of the buckets
+// from to to. If the bars are only one column wide, an arrow is annotated.
+func (h Histogram) AnnotRange(from, to int, lines ...string) *Annot {
+	a := &Annot{Col: from * h.bucketWidth(), Lines: lines}
+	if colEnd := (to+1)*h.bucketWidth() - 1; colEnd > a.Col {
+		a.ColEnd = colEnd
+	}
+	return a
+}
+
+// Quantile returns the bucket that contains the quantile q of all counts,
+// e.g. the bucket of the 99th percentile for a q of 0.99. It returns -1 if
+// the sum of the counts is 0 or less.
+func (h Histogram) Quantile(q float64) int {
+	total := 0.0
+	for _, c := range h.Counts {
+		total += max(c, 0)
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	sum := 0.0
+	for bucket, c := range h.Counts {
+		sum += max(c, 0)
+		if sum >= q*total {
+			return bucket
+		}
+	}
+	return len(h.Counts) - 1
+}
+
+func (h Histogram) bucketWidth() int {
+	return max(h.BucketWidth, 1)
+}
+
+// WriteHistogram writes the bars of the histogram h and the rendered
+// annotations below them to a writer w.
+func WriteHistogram(w io.Writer, h Histogram, annots ...*Annot) error {
+	return NewRenderer().WriteHistogram(w, h, annots...)
+}
+
+// WriteHistogram writes the bars of the histogram h and the rendered
+// annotations below them to a writer w.
+func (r *Renderer) WriteHistogram(w io.Writer, h Histogram, annots ...*Annot) error {
+	if _, err := fmt.Fprintln(w, h.String()); err != nil {
+		return err
+	}
+	return r.Write(w, annots...)
+}
@@ -0,0 +1,81 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHistogram_Quantile(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []float64
+		q      float64
+		want   int
+	}{
+		{name: "median", counts: []float64{1, 2, 4, 2, 1}, q: 0.5, want: 2},
+		{name: "p99", counts: []float64{50, 30, 10, 0, 10}, q: 0.99, want: 4},
+		{name: "zero quantile", counts: []float64{0, 3}, q: 0, want: 0},
+		{name: "no counts", counts: []float64{0, 0}, q: 0.5, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Histogram{Counts: tt.counts}).Quantile(tt.q); got != tt.want {
+				t.Errorf("Quantile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteHistogram(t *testing.T) {
+	tests := []struct {
+		name   string
+		h      Histogram
+		annots func(h Histogram) []*Annot
+		wantW  string
+	}{
+		{
+			name: "bars with blank buckets",
+			h:    Histogram{Counts: []float64{1, 2, 5, 8, 6, 3, 2, 1, 0, 0, 1, 0}},
+			annots: func(h Histogram) []*Annot {
+				return []*Annot{
+					h.Annot(3, "mode"),
+					h.Annot(h.Quantile(0.99), "p99 here"),
+				}
+			},
+			wantW: `
+▁▂▅█▆▃▂▁  ▁
+   ↑      ↑
+   │      └─ p99 here
+   └─ mode
+`,
+		},
+		{
+			name: "wide buckets",
+			h:    Histogram{Counts: []float64{4, 8, 2}, BucketWidth: 3},
+			annots: func(h Histogram) []*Annot {
+				return []*Annot{
+					h.Annot(1, "peak"),
+					h.AnnotRange(2, 2, "tail"),
+				}
+			},
+			wantW: `
+▄▄▄███▂▂▂
+   └┬┘└┬┘
+    │  └─ tail
+    │
+    └─ peak
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := WriteHistogram(w, tt.h, tt.annots(tt.h)...); err != nil {
+				t.Fatalf("WriteHistogram() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteHistogram() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
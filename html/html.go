@@ -0,0 +1,128 @@
+// Package html renders annotations as HTML, e.g. to embed them in web
+// documentation.
+//
+// The rendered annotations are a <pre class="annot"> block. Every segment
+// of an annotation is a <span> with a class of its kind and the attribute
+// data-annot with the index of the annotation, so that all segments of an
+// annotation can be styled and highlighted together:
+//
+//	<span class="annot-arrow" data-annot="0">↑</span>
+//
+// The classes are annot-arrow, annot-range, annot-pipe, annot-connector,
+// annot-text, annot-ref and annot-gap. The reference of SeeLine is a link
+// to the element with the id of the line, e.g.
+// <a class="annot-ref" href="#L42"> for line 42. WriteDoc writes these
+// elements: every line of its text is a <span class="annot-line"> with the
+// id of its 1-based line number, e.g. id="L42".
+package html
+
+import (
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/meyermarcel/annot"
+)
+
+// classes are the classes of the kinds of segments.
+var classes = map[annot.SegmentKind]string{
+	annot.ArrowSegment:     "annot-arrow",
+	annot.RangeSegment:     "annot-range",
+	annot.PipeSegment:      "annot-pipe",
+	annot.ConnectorSegment: "annot-connector",
+	annot.TextSegment:      "annot-text",
+	annot.RefSegment:       "annot-ref",
+	annot.GapSegment:       "annot-gap",
+}
+
+// Write renders the annotations as HTML and writes them to a writer w.
+func Write(w io.Writer, annots ...*annot.Annot) error {
+	l, err := annot.NewRenderer().Layout(annots...)
+	if err != nil {
+		return err
+	}
+	return WriteLayout(w, l)
+}
+
+// String returns the annotations rendered as HTML.
+func String(annots ...*annot.Annot) string {
+	b := &strings.Builder{}
+	_ = Write(b, annots...)
+	return b.String()
+}
+
+// WriteLayout writes the layout l as HTML to a writer w, e.g. a layout of
+// a Renderer with options. Segments are measured with the widths of
+// WithWidths and rows start with the columns of WithReserveLeft like in
+// Layout.Write. Styles of the Renderer are not written.
+func WriteLayout(w io.Writer, l *annot.Layout) error {
+	b := &strings.Builder{}
+	b.WriteString(`<pre class="annot">`)
+	writeRows(b, l)
+	b.WriteString("</pre>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteDoc writes the lines of a multi-line text and the annotations of
+// every line as HTML to a writer w like annot.WriteDoc. Line of an
+// annotation is the 0-based line of text. Every line is a <span> with the
+// id of its 1-based line number, so a SeeLine of 3 links to the third
+// line.
+func WriteDoc(w io.Writer, text string, annots ...*annot.Annot) error {
+	lines, layouts, err := annot.NewRenderer().DocLayouts(text, annots...)
+	if lines == nil {
+		return err
+	}
+	b := &strings.Builder{}
+	b.WriteString(`<pre class="annot">`)
+	for lIdx, line := range lines {
+		b.WriteString(`<span class="annot-line" id="L`)
+		b.WriteString(strconv.Itoa(lIdx + 1))
+		b.WriteString(`">`)
+		b.WriteString(html.EscapeString(line))
+		b.WriteString("</span>\n")
+		if layouts[lIdx] != nil {
+			writeRows(b, layouts[lIdx])
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("</pre>\n")
+	if _, writeErr := io.WriteString(w, b.String()); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// writeRows writes the rows of the layout l as HTML to b.
+func writeRows(b *strings.Builder, l *annot.Layout) {
+	l.ForEachRow(func(row int, segments []annot.Segment) {
+		if row > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(html.EscapeString(l.Reserved(row)))
+		widthWritten := 0
+		for _, s := range segments {
+			for ; widthWritten < s.Col; widthWritten++ {
+				b.WriteString(" ")
+			}
+			tag := "span"
+			if s.Kind == annot.RefSegment {
+				tag = "a"
+			}
+			b.WriteString("<" + tag + ` class="`)
+			b.WriteString(classes[s.Kind])
+			b.WriteString(`" data-annot="`)
+			b.WriteString(strconv.Itoa(s.Index))
+			if tag == "a" {
+				b.WriteString(`" href="#L`)
+				b.WriteString(strconv.Itoa(s.Annot.SeeLine))
+			}
+			b.WriteString(`">`)
+			b.WriteString(html.EscapeString(s.Text))
+			b.WriteString("</" + tag + ">")
+			widthWritten = s.Col + l.DisplayWidth(s.Text)
+		}
+	})
+}
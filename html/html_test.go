@@ -0,0 +1,125 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/meyermarcel/annot"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		annots  []*annot.Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "arrow and range",
+			annots: []*annot.Annot{
+				{Col: 0, Lines: []string{"<b>"}},
+				{Col: 4, ColEnd: 6, Lines: []string{"x", "y"}},
+			},
+			wantW: `<pre class="annot">` +
+				`<span class="annot-arrow" data-annot="0">↑</span>   <span class="annot-range" data-annot="1">└┬┘</span>` + "\n" +
+				`<span class="annot-pipe" data-annot="0">│</span>    <span class="annot-connector" data-annot="1">└─ </span><span class="annot-text" data-annot="1">x</span>` + "\n" +
+				`<span class="annot-pipe" data-annot="0">│</span>       <span class="annot-text" data-annot="1">y</span>` + "\n" +
+				`<span class="annot-connector" data-annot="0">└─ </span><span class="annot-text" data-annot="0">&lt;b&gt;</span>` +
+				"</pre>\n",
+		},
+		{
+			name: "reference",
+			annots: []*annot.Annot{
+				{Col: 0, SeeLine: 42, Lines: []string{"used"}},
+			},
+			wantW: `<pre class="annot">` +
+				`<span class="annot-arrow" data-annot="0">↑</span>` + "\n" +
+				`<span class="annot-connector" data-annot="0">└─ </span><span class="annot-text" data-annot="0">used</span>` + "\n" +
+				`   <a class="annot-ref" data-annot="0" href="#L42">→ see line 42</a>` +
+				"</pre>\n",
+		},
+		{
+			name:    "invalid annotations",
+			annots:  []*annot.Annot{nil},
+			wantErr: &annot.NilAnnotError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := Write(w, tt.annots...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestWriteDoc(t *testing.T) {
+	w := &bytes.Buffer{}
+	err := WriteDoc(w, "x := 1\ny := x\n", &annot.Annot{Col: 5, Line: 1, SeeLine: 1, Lines: []string{"x"}})
+	if err != nil {
+		t.Fatalf("WriteDoc() unexpected error = %v", err)
+	}
+	want := `<pre class="annot">` +
+		`<span class="annot-line" id="L1">x := 1</span>` + "\n" +
+		`<span class="annot-line" id="L2">y := x</span>` + "\n" +
+		`     <span class="annot-arrow" data-annot="0">↑</span>` + "\n" +
+		`     <span class="annot-connector" data-annot="0">└─ </span><span class="annot-text" data-annot="0">x</span>` + "\n" +
+		`        <a class="annot-ref" data-annot="0" href="#L1">→ see line 1</a>` + "\n" +
+		"</pre>\n"
+	if gotW := w.String(); gotW != want {
+		t.Errorf("WriteDoc() gotW = %v, want %v", gotW, want)
+	}
+
+	for _, m := range regexp.MustCompile(`href="#(L\d+)"`).FindAllStringSubmatch(w.String(), -1) {
+		if !strings.Contains(w.String(), `id="`+m[1]+`"`) {
+			t.Errorf("WriteDoc() links to %s without an element with this id", m[1])
+		}
+	}
+}
+
+func TestWriteDoc_invalid(t *testing.T) {
+	w := &bytes.Buffer{}
+	err := WriteDoc(w, "x", &annot.Annot{Col: 0, Line: 1})
+	if !errors.Is(&annot.LineOutOfRangeError{}, err) || w.Len() != 0 {
+		t.Errorf("WriteDoc() error = %v and output %q, want *LineOutOfRangeError and no output", err, w.String())
+	}
+}
+
+func TestWriteLayout_rendererOptions(t *testing.T) {
+	r := annot.NewRenderer(
+		annot.WithWidths(map[string]int{"★": 2}),
+		annot.WithReserveLeft(2, func(row int) string {
+			if row == 0 {
+				return "<E"
+			}
+			return ""
+		}),
+	)
+	l, err := r.Layout(&annot.Annot{Col: 0, Lines: []string{"★"}}, &annot.Annot{Col: 8, Lines: []string{"x"}})
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+	w := &bytes.Buffer{}
+	if err := WriteLayout(w, l); err != nil {
+		t.Fatalf("WriteLayout() unexpected error = %v", err)
+	}
+	want := `<pre class="annot">` +
+		`&lt;E<span class="annot-arrow" data-annot="0">↑</span>       <span class="annot-arrow" data-annot="1">↑</span>` + "\n" +
+		`  <span class="annot-connector" data-annot="0">└─ </span><span class="annot-text" data-annot="0">★</span>` +
+		`   <span class="annot-connector" data-annot="1">└─ </span><span class="annot-text" data-annot="1">x</span>` +
+		"</pre>\n"
+	if gotW := w.String(); gotW != want {
+		t.Errorf("WriteLayout() gotW = %v, want %v", gotW, want)
+	}
+	if plain := l.String(); plain != "<E↑       ↑\n  └─ ★   └─ x\n" {
+		t.Errorf("String() = %q, want the same columns as WriteLayout", plain)
+	}
+}
@@ -0,0 +1,130 @@
+package annot
+
+import "strings"
+
+// RequestLine is a parsed HTTP request line like "GET /index.html HTTP/1.1".
+type RequestLine struct {
+	// Line is the parsed line.
+	Line string
+
+	// Method, Target and Version are the fields called "method", "target"
+	// and "version".
+	Method, Target, Version Field
+}
+
+// ParseRequestLine parses an HTTP/1 request line. It returns a
+// *SyntaxError at the column where the line breaks the syntax, e.g. an
+// invalid character in the method or a malformed version.
+func ParseRequestLine(line string) (RequestLine, error) {
+	methodEnd := strings.IndexByte(line, ' ')
+	if methodEnd == -1 {
+		return RequestLine{}, newSyntaxError(sourceWidth(line), "missing request target")
+	}
+	if err := checkToken(line, 0, methodEnd, "method"); err != nil {
+		return RequestLine{}, err
+	}
+
+	targetStart := methodEnd + 1
+	targetEnd := strings.IndexByte(line[targetStart:], ' ')
+	if targetEnd == -1 {
+		return RequestLine{}, newSyntaxError(sourceWidth(line), "missing HTTP version")
+	}
+	targetEnd += targetStart
+	if targetEnd == targetStart {
+		return RequestLine{}, newSyntaxError(displayCol(line, targetStart), "empty request target")
+	}
+
+	versionStart := targetEnd + 1
+	if i := versionMismatch(line[versionStart:]); i != -1 {
+		return RequestLine{}, newSyntaxError(displayCol(line, versionStart+i), `version needs to be "HTTP/" digit "." digit`)
+	}
+	return RequestLine{
+		Line:    line,
+		Method:  newField("method", line, 0, methodEnd),
+		Target:  newField("target", line, targetStart, targetEnd),
+		Version: newField("version", line, versionStart, len(line)),
+	}, nil
+}
+
+// versionMismatch returns the index of the first byte of version that
+// does not match "HTTP/" digit "." digit or -1 if version matches.
+func versionMismatch(version string) int {
+	const pattern = "HTTP/0.0"
+	for i := range len(pattern) {
+		if i == len(version) {
+			return i
+		}
+		if pattern[i] == '0' {
+			if version[i] < '0' || version[i] > '9' {
+				return i
+			}
+			continue
+		}
+		if version[i] != pattern[i] {
+			return i
+		}
+	}
+	if len(version) > len(pattern) {
+		return len(pattern)
+	}
+	return -1
+}
+
+// HeaderLine is a parsed HTTP header line like "Content-Type: text/html".
+type HeaderLine struct {
+	// Line is the parsed line.
+	Line string
+
+	// Name and Value are the fields called "name" and "value". Value does
+	// not contain surrounding white space.
+	Name, Value Field
+}
+
+// ParseHeaderLine parses an HTTP/1 header line. It returns a *SyntaxError
+// at the column where the line breaks the syntax, e.g. a missing colon or
+// white space between the name and the colon.
+func ParseHeaderLine(line string) (HeaderLine, error) {
+	colonIdx := strings.IndexByte(line, ':')
+	if colonIdx == -1 {
+		return HeaderLine{}, newSyntaxError(sourceWidth(line), "missing colon")
+	}
+	if err := checkToken(line, 0, colonIdx, "header name"); err != nil {
+		return HeaderLine{}, err
+	}
+	valueStart, valueEnd := trimSpan(line, colonIdx+1, len(line))
+	return HeaderLine{
+		Line:  line,
+		Name:  newField("name", line, 0, colonIdx),
+		Value: newField("value", line, valueStart, valueEnd),
+	}, nil
+}
+
+// checkToken returns a *SyntaxError if the bytes from start to the
+// exclusive end of line are empty or not a token of RFC 9110, e.g. a
+// method or a header name described by name.
+func checkToken(line string, start, end int, name string) error {
+	if start == end {
+		return newSyntaxError(displayCol(line, start), "empty "+name)
+	}
+	for i := start; i < end; i++ {
+		if !isTokenChar(line[i]) {
+			return newSyntaxError(displayCol(line, i), "invalid character in "+name)
+		}
+	}
+	return nil
+}
+
+// isTokenChar reports whether c is a tchar of RFC 9110.
+func isTokenChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$%&'*+-.^_`|~", c) != -1
+}
+
+// displayCol returns the column of the byte index i of line, in which a
+// tab counts as one column like in the Source of a Diagnostic.
+func displayCol(line string, i int) int {
+	return sourceWidth(line[:i])
+}
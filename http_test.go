@@ -0,0 +1,149 @@
+package annot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRequestLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "valid",
+			line: "GET /index.html HTTP/1.1",
+			wantW: `
+GET /index.html HTTP/1.1
+└┬┘ └────┬────┘ └──┬───┘
+ │       │         └─ version
+ │       └─ target
+ └─ method
+`,
+		},
+		{
+			name: "lowercase version",
+			line: "GET / http/1.1",
+			wantW: `
+GET / http/1.1
+      ↑
+      └─ version needs to be "HTTP/" digit "." digit
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name: "invalid character in method",
+			line: "GE(T / HTTP/1.1",
+			wantW: `
+GE(T / HTTP/1.1
+  ↑
+  └─ invalid character in method
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name: "empty target",
+			line: "GET  HTTP/1.1",
+			wantW: `
+GET  HTTP/1.1
+    ↑
+    └─ empty request target
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "missing version",
+			line:    "GET /",
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "trailing characters after version",
+			line:    "GET / HTTP/1.10",
+			wantErr: &SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl, err := ParseRequestLine(tt.line)
+			annots, ok := fieldsOrSyntaxErr(t, err, tt.wantErr, rl.Method, rl.Target, rl.Version)
+			if !ok || tt.wantW == "" {
+				return
+			}
+			if gotW := "\n" + tt.line + "\n" + String(annots...); gotW != tt.wantW {
+				t.Errorf("ParseRequestLine() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestParseHeaderLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "valid",
+			line: "Content-Type:  text/html ",
+			wantW: `
+Content-Type:  text/html 
+└────┬─────┘   └───┬───┘
+     └─ name       └─ value
+`,
+		},
+		{
+			name: "space before colon",
+			line: "Host : example.com",
+			wantW: `
+Host : example.com
+    ↑
+    └─ invalid character in header name
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "missing colon",
+			line:    "Host example.com",
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "empty name",
+			line:    ": value",
+			wantErr: &SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl, err := ParseHeaderLine(tt.line)
+			annots, ok := fieldsOrSyntaxErr(t, err, tt.wantErr, hl.Name, hl.Value)
+			if !ok || tt.wantW == "" {
+				return
+			}
+			if gotW := "\n" + tt.line + "\n" + String(annots...); gotW != tt.wantW {
+				t.Errorf("ParseHeaderLine() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+// fieldsOrSyntaxErr checks err against wantErr and returns the labeled
+// fields or the annotation of the syntax error.
+func fieldsOrSyntaxErr(t *testing.T, err, wantErr error, fields ...Field) ([]*Annot, bool) {
+	t.Helper()
+	if wantErr == nil {
+		if err != nil {
+			t.Errorf("unexpected error = %v", err)
+			return nil, false
+		}
+		return LabelFields(fields...), true
+	}
+	var syntaxErr *SyntaxError
+	if !errors.Is(wantErr, err) || !errors.As(err, &syntaxErr) {
+		t.Errorf("error = %v, wantErr %v", err, wantErr)
+		return nil, false
+	}
+	return []*Annot{syntaxErr.Annot()}, true
+}
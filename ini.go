@@ -0,0 +1,84 @@
+package annot
+
+import "strings"
+
+// ConfigKey is a key of INI or .env content with the line of the key.
+type ConfigKey struct {
+	// LineNum is the 1-based number of Line in the content.
+	LineNum int
+
+	// Line is the line with the key and the value.
+	Line string
+
+	// Key and Value are the fields called "key" and "value". Value does
+	// not contain surrounding white space and quotes.
+	Key, Value Field
+}
+
+// FindConfigKey returns the key called key of the section called section
+// of INI or .env content. Keys before the first section header like
+// "[section]" and all keys of .env content are in the section "". Keys
+// are separated from values by "=" or ":", and lines starting with "#" or
+// ";" are comments. An "export " prefix of .env keys is ignored.
+func FindConfigKey(content, section, key string) (ConfigKey, bool) {
+	current := ""
+	for lineIdx, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "", trimmed[0] == '#', trimmed[0] == ';':
+			continue
+		case trimmed[0] == '[' && trimmed[len(trimmed)-1] == ']':
+			current = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			continue
+		case current != section:
+			continue
+		}
+
+		sepIdx := strings.IndexAny(line, "=:")
+		if sepIdx == -1 {
+			continue
+		}
+		keyStart, keyEnd := trimSpan(line, 0, sepIdx)
+		if rest, ok := strings.CutPrefix(line[keyStart:keyEnd], "export "); ok {
+			keyStart = keyEnd - len(strings.TrimLeft(rest, " \t"))
+		}
+		if line[keyStart:keyEnd] != key {
+			continue
+		}
+
+		valueStart, valueEnd := trimSpan(line, sepIdx+1, len(line))
+		if valueEnd-valueStart >= 2 && strings.ContainsAny(line[valueStart:valueStart+1], `"'`) &&
+			line[valueEnd-1] == line[valueStart] {
+			valueStart, valueEnd = valueStart+1, valueEnd-1
+		}
+		return ConfigKey{
+			LineNum: lineIdx + 1,
+			Line:    line,
+			Key:     newField("key", line, keyStart, keyEnd),
+			Value:   newField("value", line, valueStart, valueEnd),
+		}, true
+	}
+	return ConfigKey{}, false
+}
+
+// Entry returns an entry of the line of the key with annots for WriteBatch.
+// The tabs of the line are expanded and the columns of annots, in which a
+// tab counts as one column like in Key and Value, are converted to the
+// columns of the expanded line.
+func (k ConfigKey) Entry(annots ...*Annot) Entry {
+	line, annots := NewRenderer().expandSource(k.Line, annots)
+	return Entry{LineNum: k.LineNum, Line: line, Annots: annots}
+}
+
+// trimSpan returns the span from start to the exclusive end of s without
+// leading and trailing spaces and tabs.
+func trimSpan(s string, start, end int) (int, int) {
+	for start < end && (s[start] == ' ' || s[start] == '\t') {
+		start++
+	}
+	for end > start && (s[end-1] == ' ' || s[end-1] == '\t') {
+		end--
+	}
+	return start, end
+}
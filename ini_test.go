@@ -0,0 +1,122 @@
+package annot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindConfigKey(t *testing.T) {
+	ini := `; global
+name = app
+
+[server]
+host = localhost
+port: "80x"
+`
+	env := "# .env\nexport TOKEN='secret'\nDEBUG=\n"
+	tests := []struct {
+		name    string
+		content string
+		section string
+		key     string
+		annotOf func(k ConfigKey) *Annot
+		want    string
+		wantOk  bool
+	}{
+		{
+			name:    "value in section",
+			content: ini,
+			section: "server",
+			key:     "port",
+			annotOf: func(k ConfigKey) *Annot { return k.Value.Annot("invalid port") },
+			want: `
+6 │ port: "80x"
+  │        └┬┘
+  │         └─ invalid port
+`,
+			wantOk: true,
+		},
+		{
+			name:    "key before first section",
+			content: ini,
+			key:     "name",
+			annotOf: func(k ConfigKey) *Annot { return k.Key.Annot("unknown key") },
+			want: `
+2 │ name = app
+  │ └┬─┘
+  │  └─ unknown key
+`,
+			wantOk: true,
+		},
+		{
+			name:    "exported env key",
+			content: env,
+			key:     "TOKEN",
+			annotOf: func(k ConfigKey) *Annot { return k.Value.Annot("secret in plain text") },
+			want: `
+2 │ export TOKEN='secret'
+  │               └─┬──┘
+  │                 └─ secret in plain text
+`,
+			wantOk: true,
+		},
+		{
+			name:    "empty env value",
+			content: env,
+			key:     "DEBUG",
+			annotOf: func(k ConfigKey) *Annot { return k.Value.Annot("empty") },
+			want: `
+3 │ DEBUG=
+  │       ↑
+  │       └─ empty
+`,
+			wantOk: true,
+		},
+		{
+			name:    "tab-indented key",
+			content: "\tport\t= 80x",
+			key:     "port",
+			annotOf: func(k ConfigKey) *Annot { return k.Value.Annot("invalid port") },
+			want: `
+1 │         port    = 80x
+  │                   └┬┘
+  │                    └─ invalid port
+`,
+			wantOk: true,
+		},
+		{
+			name:    "key of other section",
+			content: ini,
+			key:     "host",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, ok := FindConfigKey(tt.content, tt.section, tt.key)
+			if ok != tt.wantOk {
+				t.Fatalf("FindConfigKey() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			b := &strings.Builder{}
+			err := WriteBatch(b, []Entry{k.Entry(tt.annotOf(k))})
+			if err != nil {
+				t.Fatalf("WriteBatch() unexpected error = %v", err)
+			}
+			if got := "\n" + b.String(); got != tt.want {
+				t.Errorf("FindConfigKey() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindConfigKey_tab(t *testing.T) {
+	k, ok := FindConfigKey("\tport\t= 80x", "", "port")
+	if !ok {
+		t.Fatalf("FindConfigKey() ok = %v, want %v", ok, true)
+	}
+	if k.Key.Col != 1 || k.Value.Col != 8 {
+		t.Errorf("FindConfigKey() Key.Col, Value.Col = %d, %d, want 1, 8", k.Key.Col, k.Value.Col)
+	}
+}
@@ -0,0 +1,42 @@
+package annot
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Interleave returns text with the rendered annotations of annots spliced
+// in after the line with the corresponding index. Line indexes start at 0.
+// Lines of text are kept byte-for-byte. Only a missing line break of the
+// last line is added if annotations follow it. The lowest line index that
+// is not a line of text is returned as a *LineOutOfRangeError.
+func Interleave(text string, annots map[int][]*Annot) (string, error) {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for _, lineIdx := range slices.Sorted(maps.Keys(annots)) {
+		if lineIdx < 0 || len(lines) <= lineIdx {
+			return "", newLineOutOfRangeError(lineIdx, len(lines))
+		}
+	}
+
+	b := &strings.Builder{}
+	for lineIdx, l := range lines {
+		b.WriteString(l)
+
+		lineAnnots, ok := annots[lineIdx]
+		if !ok {
+			continue
+		}
+		if !strings.HasSuffix(l, "\n") {
+			b.WriteString("\n")
+		}
+		if err := Write(b, lineAnnots...); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
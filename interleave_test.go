@@ -0,0 +1,94 @@
+package annot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		annots  map[int][]*Annot
+		want    string
+		wantErr error
+	}{
+		{
+			name: "no annotations",
+			text: "a\r\nb\n",
+			want: "a\r\nb\n",
+		},
+		{
+			name: "annotations after lines",
+			text: "first\nsecond\nthird\n",
+			annots: map[int][]*Annot{
+				0: {{Col: 1, Lines: []string{"i"}}},
+				2: {{Col: 0, ColEnd: 4, Lines: []string{"third"}}},
+			},
+			want: `first
+ ↑
+ └─ i
+second
+third
+└─┬─┘
+  └─ third
+`,
+		},
+		{
+			name: "keep carriage return",
+			text: "a\r\nb",
+			annots: map[int][]*Annot{
+				0: {{Col: 0}},
+			},
+			want: "a\r\n↑\n└─ \nb",
+		},
+		{
+			name: "add line break to last line",
+			text: "last",
+			annots: map[int][]*Annot{
+				0: {{Col: 0, Lines: []string{"l"}}},
+			},
+			want: "last\n↑\n└─ l\n",
+		},
+		{
+			name: "line out of range",
+			text: "a\n",
+			annots: map[int][]*Annot{
+				1: {{Col: 0}},
+			},
+			wantErr: &LineOutOfRangeError{},
+		},
+		{
+			name: "annotation error",
+			text: "ab\n",
+			annots: map[int][]*Annot{
+				0: {{Col: 1, ColEnd: 1}},
+			},
+			wantErr: &ColExceedsColEndError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Interleave(tt.text, tt.annots)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("Interleave() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Interleave() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterleave_lowestOutOfRangeLine(t *testing.T) {
+	annots := map[int][]*Annot{0: {{Col: 0}}, 7: {{Col: 0}}, -1: {{Col: 0}}}
+	want := "annot: line -1 is out of range of 1 lines"
+	for range 20 {
+		if _, err := Interleave("a\n", annots); err == nil || err.Error() != want {
+			t.Fatalf("Interleave() error = %v, want %v", err, want)
+		}
+	}
+}
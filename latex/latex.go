@@ -0,0 +1,106 @@
+// Package latex exports annotations of a line as LaTeX, e.g. to typeset
+// annotated sentences in papers and teaching material.
+//
+// Every annotated range of the line is put under a brace with the lines of
+// its annotation below:
+//
+//	The $\underbrace{\text{quick}}_{\text{adjective}}$ fox
+//
+// An annotation without ColEnd puts a brace under the character at Col.
+// Multiple lines of an annotation are stacked with \substack of amsmath.
+package latex
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/meyermarcel/annot"
+	"github.com/rivo/uniseg"
+)
+
+// Write writes line with the annotations as LaTeX to a writer w. Columns
+// right of the end of line annotate spaces. Annotations are validated like
+// by annot.Write.
+func Write(w io.Writer, line string, annots ...*annot.Annot) error {
+	if _, err := annot.NewRenderer(annot.WithStrict()).Layout(annots...); err != nil {
+		return err
+	}
+	sorted := slices.Clone(annots)
+	slices.SortStableFunc(sorted, func(a, b *annot.Annot) int {
+		return a.Col - b.Col
+	})
+
+	cells := cells(line)
+	b := &strings.Builder{}
+	col := 0
+	for _, a := range sorted {
+		colEnd := max(a.Col, a.ColEnd)
+		for len(cells) <= colEnd {
+			cells = append(cells, " ")
+		}
+		b.WriteString(escape(strings.Join(cells[col:a.Col], "")))
+		b.WriteString(`$\underbrace{\text{`)
+		b.WriteString(escape(strings.Join(cells[a.Col:colEnd+1], "")))
+		b.WriteString(`}}_{`)
+		b.WriteString(label(a.Lines))
+		b.WriteString(`}$`)
+		col = colEnd + 1
+	}
+	b.WriteString(escape(strings.Join(cells[min(col, len(cells)):], "")))
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String returns line with the annotations as LaTeX.
+func String(line string, annots ...*annot.Annot) string {
+	b := &strings.Builder{}
+	_ = Write(b, line, annots...)
+	return b.String()
+}
+
+// cells returns the grapheme clusters of s by display column. A wide
+// character is followed by empty cells for the columns it covers.
+func cells(s string) []string {
+	var cells []string
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		cells = append(cells, g.Str())
+		for i := 1; i < g.Width(); i++ {
+			cells = append(cells, "")
+		}
+	}
+	return cells
+}
+
+// label returns the lines as text of math mode.
+func label(lines []string) string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = `\text{` + escape(l) + `}`
+	}
+	if len(texts) == 1 {
+		return texts[0]
+	}
+	return `\substack{` + strings.Join(texts, `\\`) + `}`
+}
+
+// escaper escapes the special characters of LaTeX.
+var escaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`^`, `\^{}`,
+	`_`, `\_`,
+	`%`, `\%`,
+	`~`, `\~{}`,
+)
+
+// escape returns s with the special characters of LaTeX escaped.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
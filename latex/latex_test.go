@@ -0,0 +1,69 @@
+package latex
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/meyermarcel/annot"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		annots  []*annot.Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "ranges and columns",
+			line: "The quick fox",
+			annots: []*annot.Annot{
+				{Col: 10, ColEnd: 12, Lines: []string{"noun"}},
+				{Col: 4, ColEnd: 8, Lines: []string{"adjective", "50% off"}},
+				{Col: 0, Lines: []string{"T"}},
+			},
+			wantW: `$\underbrace{\text{T}}_{\text{T}}$he ` +
+				`$\underbrace{\text{quick}}_{\substack{\text{adjective}\\\text{50\% off}}}$ ` +
+				`$\underbrace{\text{fox}}_{\text{noun}}$` + "\n",
+		},
+		{
+			name: "column after end of line",
+			line: "a_b",
+			annots: []*annot.Annot{
+				{Col: 4, Lines: []string{"missing }"}},
+			},
+			wantW: `a\_b $\underbrace{\text{ }}_{\text{missing \}}}$` + "\n",
+		},
+		{
+			name: "wide characters",
+			line: "世界 x",
+			annots: []*annot.Annot{
+				{Col: 2, ColEnd: 3, Lines: []string{"界"}},
+			},
+			wantW: `世$\underbrace{\text{界}}_{\text{界}}$ x` + "\n",
+		},
+		{
+			name: "overlapping annotations",
+			line: "abc",
+			annots: []*annot.Annot{
+				{Col: 0, ColEnd: 2},
+				{Col: 1},
+			},
+			wantErr: &annot.OverlapError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := Write(w, tt.line, tt.annots...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package annot
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// SegmentKind is the kind of a rendered Segment.
+type SegmentKind int
+
+const (
+	// ArrowSegment is an arrowhead in the first row, e.g. "↑".
+	ArrowSegment SegmentKind = iota
+
+	// RangeSegment is an annotated range in the first row, e.g. "└─┬─┘".
+	RangeSegment
+
+	// PipeSegment is a part of a stem below the first row, e.g. "│".
+	PipeSegment
+
+	// ConnectorSegment connects a stem with the first line, e.g. "└─ ".
+	ConnectorSegment
+
+	// TextSegment is a line of the text of an annotation.
+	TextSegment
+
+	// RefSegment is the reference of SeeLine, e.g. "→ see line 42".
+	RefSegment
+
+	// GapSegment is the marker of collapsed blank columns of
+	// WithGapCompression in the first row, e.g. "⋯+240⋯". It does not
+	// belong to an annotation.
+	GapSegment
+)
+
+// Segment is a part of a rendered row that belongs to an annotation.
+type Segment struct {
+	// Col is the column where the segment starts.
+	Col int
+
+	// Text is the rendered text of the segment.
+	Text string
+
+	// Kind is the kind of the segment.
+	Kind SegmentKind
+
+	// Annot is the annotation the segment belongs to.
+	Annot *Annot
+
+	// Index is the index of Annot in the annotations passed to the
+	// Renderer.
+	Index int
+}
+
+// Cell is a single cell of a grid of rendered rows.
+type Cell struct {
+	// Text is the grapheme cluster drawn in the cell. It is a space for
+	// padding and empty for the cells covered by a wide character.
+	Text string
+
+	// Index is the index of the annotation the cell belongs to in the
+	// annotations passed to the Renderer or -1 for padding.
+	Index int
+}
+
+// Bounds is the smallest rectangle of a layout containing all segments of
+// an annotation.
+type Bounds struct {
+	// Col and Row are the column and row of the upper left corner.
+	Col, Row int
+
+	// Width and Height are the number of columns and rows.
+	Width, Height int
+}
+
+// Layout is the result of laying out annotations. Rows consist of
+// segments, so consumers can build their own output from a layout.
+type Layout struct {
+	r      *Renderer
+	annots []*Annot
+	width  int
+	rows   [][]Segment
+}
+
+// Layout lays out the annotations. If the Renderer skips invalid
+// annotations, Layout returns a layout of the valid annotations together
+// with a *SkippedError.
+func (r *Renderer) Layout(annots ...*Annot) (*Layout, error) {
+	annots, skipped, err := r.arrange(annots)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Layout{r: r, annots: annots, width: r.width}
+	if len(annots) != 0 {
+		if l.rows, err = r.placeCached(annots, l.width); err != nil {
+			return nil, err
+		}
+		l.appendSummary()
+	}
+
+	if len(skipped) != 0 {
+		return l, newSkippedError(skipped)
+	}
+	return l, nil
+}
+
+// Reflow lays out the annotations again with rows limited to width
+// columns. Lines of annotations that did not change since the last layout
+// are not measured again. A width of 0 or less does not limit rows. If a
+// strict Renderer returns a *ClippedError, the layout is unchanged.
+func (l *Layout) Reflow(width int) error {
+	if len(l.annots) != 0 {
+		rows, err := l.r.place(l.annots, width)
+		if err != nil {
+			return err
+		}
+		l.rows = rows
+		l.width = width
+		l.appendSummary()
+	}
+	l.width = width
+	return nil
+}
+
+// Collapsed returns the annotations whose lines were collapsed, ordered
+// by their columns.
+func (l *Layout) Collapsed() []*Annot {
+	var collapsed []*Annot
+	for _, a := range l.annots {
+		if a.collapsed {
+			collapsed = append(collapsed, a)
+		}
+	}
+	return collapsed
+}
+
+// RowCount returns the number of rendered rows.
+func (l *Layout) RowCount() int {
+	return len(l.rows)
+}
+
+// Bounds returns the bounds of the segments of the annotation a. It
+// returns false if a is not part of the layout.
+func (l *Layout) Bounds(a *Annot) (Bounds, bool) {
+	colEnd, rowEnd := 0, 0
+	b := Bounds{Col: -1}
+	for row, segments := range l.rows {
+		for _, s := range segments {
+			if s.Annot != a {
+				continue
+			}
+			if b.Col == -1 {
+				b.Col, b.Row = s.Col, row
+			}
+			b.Col = min(b.Col, s.Col)
+			colEnd = max(colEnd, s.Col+l.r.stringWidth(s.Text))
+			rowEnd = row + 1
+		}
+	}
+	if b.Col == -1 {
+		return Bounds{}, false
+	}
+	b.Width = colEnd - b.Col
+	b.Height = rowEnd - b.Row
+	return b, true
+}
+
+// ForEachRow calls fn for every row of the layout. The first row with
+// index 0 contains the arrowheads and ranges. The segments of a row are
+// ordered by their columns and do not overlap.
+func (l *Layout) ForEachRow(fn func(row int, segments []Segment)) {
+	for row, segments := range l.rows {
+		fn(row, segments)
+	}
+}
+
+// Reserved returns the columns of WithReserveLeft in front of row as
+// written by Write.
+func (l *Layout) Reserved(row int) string {
+	return string(l.r.appendReserved(nil, row))
+}
+
+// DisplayWidth returns the display width of s like the Renderer of the
+// layout measures segments, e.g. with the widths of WithWidths.
+func (l *Layout) DisplayWidth(s string) int {
+	return l.r.stringWidth(s)
+}
+
+// Write writes the rendered layout to a writer w.
+func (l *Layout) Write(w io.Writer) error {
+	return l.writeRows(w, 0)
+}
+
+// writeRows writes the rendered rows starting at row from to a writer w.
+// Rows are appended directly to the buffer of a *bufio.Writer.
+func (l *Layout) writeRows(w io.Writer, from int) error {
+	rows := l.rows[min(from, len(l.rows)):]
+	plain := !l.r.colored(w)
+	if bw, ok := w.(*bufio.Writer); ok {
+		for rIdx, segments := range rows {
+			if _, err := bw.Write(l.appendRow(bw.AvailableBuffer(), from+rIdx, segments, plain)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if l.r.blockWrite && !inMemory(w) {
+		var buf []byte
+		for rIdx, segments := range rows {
+			buf = l.appendRow(buf, from+rIdx, segments, plain)
+		}
+		_, err := w.Write(buf)
+		return err
+	}
+	var buf []byte
+	for rIdx, segments := range rows {
+		buf = l.appendRow(buf[:0], from+rIdx, segments, plain)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inMemory reports whether w writes to memory, so that writing per row
+// does not cause syscalls. *os.File implements io.StringWriter too, so
+// the types are checked instead.
+func inMemory(w io.Writer) bool {
+	switch w.(type) {
+	case *bytes.Buffer, *strings.Builder:
+		return true
+	}
+	return false
+}
+
+// AppendTo appends the rendered layout to dst and returns the extended
+// buffer.
+func (l *Layout) AppendTo(dst []byte) []byte {
+	plain := !l.r.colored(nil)
+	for row, segments := range l.rows {
+		dst = l.appendRow(dst, row, segments, plain)
+	}
+	return dst
+}
+
+// appendRow appends the reserved columns, the rendered segments of a row
+// and a newline to dst. Segments are not styled if plain is set. Trailing
+// whitespace of the row is removed in canonical mode.
+func (l *Layout) appendRow(dst []byte, row int, segments []Segment, plain bool) []byte {
+	start := len(dst)
+	dst = l.r.appendReserved(dst, row)
+	widthWritten := 0
+	for _, s := range segments {
+		for ; widthWritten < s.Col; widthWritten++ {
+			dst = append(dst, ' ')
+		}
+		if plain {
+			dst = append(dst, s.Text...)
+		} else {
+			dst = appendStyled(dst, l.r.style(s), s.Text)
+		}
+		widthWritten = s.Col + l.r.stringWidth(s.Text)
+	}
+	if l.r.canonical {
+		dst = dst[:start+len(bytes.TrimRight(dst[start:], " \t\r"))]
+	}
+	return append(dst, '\n')
+}
+
+// Check verifies the invariants of the layout: segments of a row are
+// ordered by their columns and do not overlap, columns are not negative,
+// and arrowheads, ranges and gap markers are only in the first row or, for
+// WithLinear, rows contain only text and references. It returns an
+// *InvariantError for the first violation, e.g. for tests of custom styles
+// or backends.
+func (l *Layout) Check() error {
+	for row, segments := range l.rows {
+		end := 0
+		for sIdx, s := range segments {
+			switch {
+			case s.Col < 0:
+				return newInvariantError(row, s.Col, "column is negative")
+			case sIdx > 0 && s.Col < end:
+				return newInvariantError(row, s.Col, "segment overlaps previous segment")
+			case l.r.linear && s.Kind != TextSegment && s.Kind != RefSegment:
+				return newInvariantError(row, s.Col, "linear layout contains segment other than text or reference")
+			case l.r.linear:
+			case row == 0 && s.Kind != ArrowSegment && s.Kind != RangeSegment && s.Kind != GapSegment:
+				return newInvariantError(row, s.Col, "first row contains segment other than arrowhead, range or gap marker")
+			case row != 0 && (s.Kind == ArrowSegment || s.Kind == RangeSegment || s.Kind == GapSegment):
+				return newInvariantError(row, s.Col, "arrowhead, range or gap marker after first row")
+			}
+			end = s.Col + l.r.stringWidth(s.Text)
+		}
+	}
+	return nil
+}
+
+// Grid returns the rendered rows as cells. Every cell is tagged with the
+// index of the annotation that produced it. Rows are not padded to the
+// same width.
+func (l *Layout) Grid() [][]Cell {
+	grid := make([][]Cell, len(l.rows))
+	for row, segments := range l.rows {
+		var cells []Cell
+		for _, s := range segments {
+			for len(cells) < s.Col {
+				cells = append(cells, Cell{Text: " ", Index: -1})
+			}
+			g := uniseg.NewGraphemes(s.Text)
+			for g.Next() {
+				cells = append(cells, Cell{Text: g.Str(), Index: s.Index})
+				for i := 1; i < l.r.stringWidth(g.Str()); i++ {
+					cells = append(cells, Cell{Index: s.Index})
+				}
+			}
+		}
+		grid[row] = cells
+	}
+	return grid
+}
+
+// String returns the rendered layout as a string.
+func (l *Layout) String() string {
+	b := &strings.Builder{}
+	_ = l.Write(b)
+	return b.String()
+}
+
+// requiredWidth returns the display width of the widest row including the
+// reserved columns.
+func (l *Layout) requiredWidth() int {
+	width := 0
+	for _, segments := range l.rows {
+		if len(segments) == 0 {
+			continue
+		}
+		last := segments[len(segments)-1]
+		width = max(width, last.Col+l.r.stringWidth(last.Text))
+	}
+	if width == 0 {
+		return 0
+	}
+	return l.r.reserveLeft + width
+}
@@ -0,0 +1,353 @@
+package annot
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestLayout_ForEachRow(t *testing.T) {
+	a1 := &Annot{Col: 0, ColEnd: 2, Lines: []string{"line1"}}
+	a2 := &Annot{Col: 4, Lines: []string{"line1", "line2"}}
+	a3 := &Annot{Col: 6}
+
+	l, err := NewRenderer().Layout(a1, a3, a2)
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+
+	want := [][]Segment{
+		{
+			{Col: 0, Text: "└┬┘", Kind: RangeSegment, Annot: a1, Index: 0},
+			{Col: 4, Text: "↑", Kind: ArrowSegment, Annot: a2, Index: 2},
+			{Col: 6, Text: "↑", Kind: ArrowSegment, Annot: a3, Index: 1},
+		},
+		{
+			{Col: 1, Text: "│", Kind: PipeSegment, Annot: a1, Index: 0},
+			{Col: 4, Text: "│", Kind: PipeSegment, Annot: a2, Index: 2},
+			{Col: 6, Text: "└─ ", Kind: ConnectorSegment, Annot: a3, Index: 1},
+		},
+		{
+			{Col: 1, Text: "│", Kind: PipeSegment, Annot: a1, Index: 0},
+			{Col: 4, Text: "│", Kind: PipeSegment, Annot: a2, Index: 2},
+		},
+		{
+			{Col: 1, Text: "│", Kind: PipeSegment, Annot: a1, Index: 0},
+			{Col: 4, Text: "└─ ", Kind: ConnectorSegment, Annot: a2, Index: 2},
+			{Col: 7, Text: "line1", Kind: TextSegment, Annot: a2, Index: 2},
+		},
+		{
+			{Col: 1, Text: "│", Kind: PipeSegment, Annot: a1, Index: 0},
+			{Col: 7, Text: "line2", Kind: TextSegment, Annot: a2, Index: 2},
+		},
+		{
+			{Col: 1, Text: "│", Kind: PipeSegment, Annot: a1, Index: 0},
+		},
+		{
+			{Col: 1, Text: "└─ ", Kind: ConnectorSegment, Annot: a1, Index: 0},
+			{Col: 4, Text: "line1", Kind: TextSegment, Annot: a1, Index: 0},
+		},
+	}
+
+	var got [][]Segment
+	l.ForEachRow(func(row int, segments []Segment) {
+		if row != len(got) {
+			t.Errorf("ForEachRow() row = %v, want %v", row, len(got))
+		}
+		got = append(got, segments)
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ForEachRow() got = %v, want %v", got, want)
+	}
+
+	wantString := `└┬┘ ↑ ↑
+ │  │ └─ 
+ │  │
+ │  └─ line1
+ │     line2
+ │
+ └─ line1
+`
+	if gotString := l.String(); gotString != wantString {
+		t.Errorf("String() got = %v, want %v", gotString, wantString)
+	}
+}
+
+func TestLayout_noAnnots(t *testing.T) {
+	l, err := NewRenderer().Layout()
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+	l.ForEachRow(func(int, []Segment) {
+		t.Errorf("ForEachRow() called for layout without annotations")
+	})
+	if got := l.String(); got != "" {
+		t.Errorf("String() got = %q, want empty string", got)
+	}
+}
+
+func TestLayout_Grid(t *testing.T) {
+	l, err := NewRenderer().Layout(
+		&Annot{Col: 3, Lines: []string{"漢"}},
+		&Annot{Col: 0, ColEnd: 1},
+	)
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+
+	want := [][]Cell{
+		{{"├", 1}, {"┘", 1}, {" ", -1}, {"↑", 0}},
+		{{"│", 1}, {" ", -1}, {" ", -1}, {"└", 0}, {"─", 0}, {" ", 0}, {"漢", 0}, {"", 0}},
+		{{"└", 1}, {"─", 1}, {" ", 1}},
+	}
+	if got := l.Grid(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Grid() got = %v, want %v", got, want)
+	}
+}
+
+func TestLayout_Reflow(t *testing.T) {
+	a1 := &Annot{Col: 0, Lines: []string{"the first annotation wraps"}}
+	a2 := &Annot{Col: 10, Lines: []string{"second annotation"}}
+
+	l, err := NewRenderer(WithWidth(24)).Layout(a1, a2)
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+	want := `↑         ↑
+│         └─ second
+│            annotation
+│
+└─ the first annotation
+   wraps
+`
+	if got := l.String(); got != want {
+		t.Errorf("String() got = %v, want %v", got, want)
+	}
+
+	measured := &a1.measured[0].segments[0]
+
+	if err := l.Reflow(16); err != nil {
+		t.Fatalf("Reflow() unexpected error = %v", err)
+	}
+	want = `↑         ↑
+│         └─ second
+│            annotation
+└─ the first
+   annotation
+   wraps
+`
+	if got := l.String(); got != want {
+		t.Errorf("String() after Reflow(16) got = %v, want %v", got, want)
+	}
+	if &a1.measured[0].segments[0] != measured {
+		t.Errorf("Reflow() measured unchanged lines again")
+	}
+
+	if err := l.Reflow(0); err != nil {
+		t.Fatalf("Reflow() unexpected error = %v", err)
+	}
+	want = `↑         ↑
+│         └─ second annotation
+│
+└─ the first annotation wraps
+`
+	if got := l.String(); got != want {
+		t.Errorf("String() after Reflow(0) got = %v, want %v", got, want)
+	}
+}
+
+func TestLayout_Collapsed(t *testing.T) {
+	a1 := &Annot{Col: 0, Lines: []string{"line1", "line2", "line3", "line4"}}
+	a2 := &Annot{Col: 10, Lines: []string{"line1", "line2", "line3"}}
+	a3 := &Annot{Col: 20, Lines: []string{"line1", "line2"}}
+
+	l, err := NewRenderer(WithCollapse(2)).Layout(a1, a2, a3)
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+
+	want := `↑         ↑         ↑
+│         │         └─ line1
+│         └─ line1     line2
+│            line2
+└─ line1     … (+1 more line)
+   line2
+   … (+2 more lines)
+`
+	if got := l.String(); got != want {
+		t.Errorf("String() got = %v, want %v", got, want)
+	}
+
+	if got := l.Collapsed(); !reflect.DeepEqual(got, []*Annot{a1, a2}) {
+		t.Errorf("Collapsed() got = %v, want %v", got, []*Annot{a1, a2})
+	}
+}
+
+func TestLayout_Bounds(t *testing.T) {
+	a1 := &Annot{Col: 0, ColEnd: 2, Lines: []string{"line1"}}
+	a2 := &Annot{Col: 4, Lines: []string{"line1", "line2"}}
+	a3 := &Annot{Col: 6}
+	notRendered := &Annot{Col: 8}
+
+	l, err := NewRenderer().Layout(a1, a2, a3)
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+
+	if got := l.RowCount(); got != 7 {
+		t.Errorf("RowCount() = %v, want %v", got, 7)
+	}
+
+	tests := []struct {
+		name   string
+		a      *Annot
+		want   Bounds
+		wantOk bool
+	}{
+		{name: "range", a: a1, want: Bounds{Col: 0, Row: 0, Width: 9, Height: 7}, wantOk: true},
+		{name: "multiple lines", a: a2, want: Bounds{Col: 4, Row: 0, Width: 8, Height: 5}, wantOk: true},
+		{name: "without lines", a: a3, want: Bounds{Col: 6, Row: 0, Width: 3, Height: 2}, wantOk: true},
+		{name: "not rendered", a: notRendered, want: Bounds{}, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := l.Bounds(tt.a)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Bounds() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLayout_Check(t *testing.T) {
+	a := &Annot{Col: 0}
+	tests := []struct {
+		name    string
+		rows    [][]Segment
+		wantErr error
+	}{
+		{
+			name: "valid",
+			rows: [][]Segment{
+				{{Col: 0, Text: "↑", Kind: ArrowSegment, Annot: a}, {Col: 2, Text: "└┘", Kind: RangeSegment, Annot: a}},
+				{{Col: 0, Text: "└─ ", Kind: ConnectorSegment, Annot: a}, {Col: 3, Text: "line1", Kind: TextSegment, Annot: a}},
+			},
+		},
+		{
+			name: "overlapping segments",
+			rows: [][]Segment{
+				{{Col: 0, Text: "↑", Kind: ArrowSegment, Annot: a}},
+				{{Col: 0, Text: "└─ ", Kind: ConnectorSegment, Annot: a}, {Col: 2, Text: "line1", Kind: TextSegment, Annot: a}},
+			},
+			wantErr: &InvariantError{},
+		},
+		{
+			name: "unordered segments",
+			rows: [][]Segment{
+				{{Col: 4, Text: "↑", Kind: ArrowSegment, Annot: a}, {Col: 1, Text: "↑", Kind: ArrowSegment, Annot: a}},
+			},
+			wantErr: &InvariantError{},
+		},
+		{
+			name: "negative column",
+			rows: [][]Segment{
+				{{Col: -1, Text: "↑", Kind: ArrowSegment, Annot: a}},
+			},
+			wantErr: &InvariantError{},
+		},
+		{
+			name: "text in first row",
+			rows: [][]Segment{
+				{{Col: 0, Text: "line1", Kind: TextSegment, Annot: a}},
+			},
+			wantErr: &InvariantError{},
+		},
+		{
+			name: "arrowhead after first row",
+			rows: [][]Segment{
+				{},
+				{{Col: 0, Text: "↑", Kind: ArrowSegment, Annot: a}},
+			},
+			wantErr: &InvariantError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Layout{r: NewRenderer(), rows: tt.rows}
+			err := l.Check()
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLayout_Check_rendered(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithMarginNotes(20)}, {WithConnector("└──▶ ")}, {WithWidth(12)}} {
+		l, err := NewRenderer(opts...).Layout(
+			&Annot{Col: 0, ColEnd: 4, Lines: []string{"line1", "line2"}},
+			&Annot{Col: 6, Lines: []string{"a long line that wraps"}},
+			&Annot{Col: 8, ColEnd: 9},
+		)
+		if err != nil {
+			t.Fatalf("Layout() unexpected error = %v", err)
+		}
+		if err := l.Check(); err != nil {
+			t.Errorf("Check() error = %v\n%v", err, l)
+		}
+	}
+}
+
+type countingWriter struct {
+	writes int
+	b      []byte
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.b = append(w.b, p...)
+	return len(p), nil
+}
+
+func TestLayout_Write_writes(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Lines: []string{"line1"}},
+		{Col: 4, Lines: []string{"line1", "line2"}},
+	}
+	want := String(annots...)
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		buffered   bool
+		wantWrites int
+	}{
+		{name: "per row", wantWrites: 5},
+		{name: "block", opts: []Option{WithBlockWrite()}, wantWrites: 1},
+		{name: "bufio writer", opts: []Option{WithBlockWrite()}, buffered: true, wantWrites: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cw := &countingWriter{}
+			var w io.Writer = cw
+			bw := bufio.NewWriter(cw)
+			if tt.buffered {
+				w = bw
+			}
+			if err := NewRenderer(tt.opts...).Write(w, annots...); err != nil {
+				t.Fatalf("Write() unexpected error = %v", err)
+			}
+			if err := bw.Flush(); err != nil {
+				t.Fatalf("Flush() unexpected error = %v", err)
+			}
+			if cw.writes != tt.wantWrites {
+				t.Errorf("Write() writes = %v, want %v", cw.writes, tt.wantWrites)
+			}
+			if got := string(cw.b); got != want {
+				t.Errorf("Write() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package annot
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// WithLinear renders the annotations as a numbered list ordered by their
+// columns instead of drawing them, e.g. for screen readers:
+//
+//  1. column 0: article
+//  2. columns 4–11, warning: adjective
+//     second line
+//
+// The severity of an annotation is named if it is set. The rows of a
+// Layout contain only text segments, the first row as well.
+func WithLinear() Option {
+	return func(r *Renderer) {
+		r.linear = true
+	}
+}
+
+// linearRows returns the rendered rows of annotations as a numbered list.
+func (r *Renderer) linearRows(annots []*Annot) [][]Segment {
+	rows := make([][]Segment, 0, len(annots))
+	for _, a := range annots {
+		a.row = len(rows)
+		number := strconv.Itoa(a.num) + ". "
+		item := number + columns(a)
+		if a.Severity != SeverityNone {
+			item += ", " + a.Severity.String()
+		}
+		item += ":"
+		if len(a.Lines) != 0 {
+			item += " " + a.Lines[0]
+		}
+		rows = append(rows, []Segment{{Text: item, Kind: TextSegment, Annot: a, Index: a.idx}})
+		for _, l := range a.Lines[min(1, len(a.Lines)):] {
+			var segments []Segment
+			rows = append(rows, appendText(segments, len(number), l, a))
+		}
+		if a.SeeLine != 0 {
+			rows = append(rows, []Segment{{Col: len(number), Text: r.seeLine(a.SeeLine), Kind: RefSegment, Annot: a, Index: a.idx}})
+		}
+	}
+	return rows
+}
+
+// columns returns the columns of a in words, e.g. "column 4" or
+// "columns 4–11".
+func columns(a *Annot) string {
+	if a.ColEnd != 0 {
+		return fmt.Sprintf("columns %d–%d", a.Col, a.ColEnd)
+	}
+	return "column " + strconv.Itoa(a.Col)
+}
@@ -0,0 +1,56 @@
+package annot
+
+import (
+	"testing"
+)
+
+func TestWithLinear(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		annots []*Annot
+		wantW  string
+	}{
+		{
+			name: "numbered list",
+			annots: []*Annot{
+				{Col: 4, ColEnd: 11, Severity: SeverityWarning, Lines: []string{"adjective", "second line"}},
+				{Col: 0, Lines: []string{"article"}},
+				{Col: 13, SeeLine: 1},
+			},
+			wantW: `
+1. column 0: article
+2. columns 4–11, warning: adjective
+   second line
+3. column 13:
+   → see line 1
+`,
+		},
+		{
+			name: "summary and origin",
+			opts: []Option{WithOrigin(1), WithSummary()},
+			annots: []*Annot{
+				{Col: 1, Severity: SeverityError, Lines: []string{"error"}},
+			},
+			wantW: `
+1. column 1, error: error
+1 annotation: 1 error
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(append([]Option{WithLinear()}, tt.opts...)...)
+			l, err := r.Layout(tt.annots...)
+			if err != nil {
+				t.Fatalf("Layout() unexpected error = %v", err)
+			}
+			if gotW := "\n" + l.String(); gotW != tt.wantW {
+				t.Errorf("String() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if err := l.Check(); err != nil {
+				t.Errorf("Check() unexpected error = %v", err)
+			}
+		})
+	}
+}
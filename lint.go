@@ -0,0 +1,58 @@
+package annot
+
+import (
+	"io"
+	"strings"
+)
+
+// LintDiagnostic returns a warning of message about the 1-based line of
+// content, e.g. a Dockerfile or Makefile, for the line and column reported
+// by linters like hadolint or checkmake. The 1-based byte column col gets
+// an arrow annotation and a col of 0 annotates the whole line. Source and
+// the columns keep the tabs of the line like in ParseCompilerOutput.
+func LintDiagnostic(content string, line, col int, message string) (*Diagnostic, error) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if line < 1 || line > len(lines) {
+		return nil, newLineOutOfRangeError(line, len(lines))
+	}
+	source := strings.TrimSuffix(lines[line-1], "\r")
+
+	d := &Diagnostic{
+		Line:     line,
+		Source:   source,
+		Severity: SeverityWarning,
+		Message:  message,
+	}
+	a := lineAnnot(source)
+	if col > 0 {
+		a = &Annot{Col: byteColToSourceCol(source, col-1)}
+	}
+	if a != nil {
+		d.Annots = []*Annot{a}
+	}
+	return d, nil
+}
+
+// WriteLint writes the line of content with a line number gutter and
+// message annotated at the column col like in LintDiagnostic.
+func WriteLint(w io.Writer, content string, line, col int, message string) error {
+	return NewRenderer().WriteLint(w, content, line, col, message)
+}
+
+// WriteLint writes the line of content with a line number gutter and
+// message annotated at the column col like in LintDiagnostic.
+func (r *Renderer) WriteLint(w io.Writer, content string, line, col int, message string) error {
+	d, err := LintDiagnostic(content, line, col, message)
+	if err != nil {
+		return err
+	}
+	annots := d.Annots
+	if len(annots) == 0 {
+		annots = []*Annot{{}}
+	}
+	for _, a := range annots {
+		a.Lines = []string{message}
+	}
+	source, annots := r.expandSource(d.Source, annots)
+	return r.WriteBatch(w, []Entry{{LineNum: d.Line, Line: source, Annots: annots}})
+}
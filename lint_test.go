@@ -0,0 +1,99 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteLint(t *testing.T) {
+	dockerfile := "FROM debian\nRUN apt-get install curl\n"
+	makefile := "all:\n\tgo build ./...\n"
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		col     int
+		message string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name:    "column",
+			content: dockerfile,
+			line:    2,
+			col:     5,
+			message: "DL3008 Pin versions in apt get install",
+			wantW: `
+2 │ RUN apt-get install curl
+  │     ↑
+  │     └─ DL3008 Pin versions in apt get install
+`,
+		},
+		{
+			name:    "whole line with tab",
+			content: makefile,
+			line:    2,
+			message: "minphony: missing required phony target",
+			wantW: `
+2 │         go build ./...
+  │         └─────┬──────┘
+  │               └─ minphony: missing required phony target
+`,
+		},
+		{
+			name:    "line out of range",
+			content: dockerfile,
+			line:    3,
+			wantErr: &LineOutOfRangeError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := WriteLint(w, tt.content, tt.line, tt.col, tt.message)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("WriteLint() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteLint() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteLint() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestLintDiagnostic(t *testing.T) {
+	d, err := LintDiagnostic("a\nb\r\n", 2, 0, "message")
+	if err != nil {
+		t.Fatalf("LintDiagnostic() unexpected error = %v", err)
+	}
+	if d.Source != "b" || d.Severity != SeverityWarning || len(d.Annots) != 1 || d.Annots[0].Col != 0 {
+		t.Errorf("LintDiagnostic() = %+v, want warning of line b", d)
+	}
+}
+
+func TestLintDiagnostic_tabs(t *testing.T) {
+	d, err := LintDiagnostic("all:\n\tgo build\n", 2, 2, "message")
+	if err != nil {
+		t.Fatalf("LintDiagnostic() unexpected error = %v", err)
+	}
+	if d.Source != "\tgo build" || len(d.Annots) != 1 || d.Annots[0].Col != 1 {
+		t.Errorf("LintDiagnostic() = %+v, want source with tab and Col 1", d)
+	}
+
+	d.File = "Makefile"
+	w := &bytes.Buffer{}
+	if err := WriteGitHubActions(w, d); err != nil {
+		t.Fatalf("WriteGitHubActions() unexpected error = %v", err)
+	}
+	want := "::warning file=Makefile,line=2,col=2,endColumn=2::message\n"
+	if got := w.String(); got != want {
+		t.Errorf("WriteGitHubActions() got = %q, want %q", got, want)
+	}
+}
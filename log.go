@@ -0,0 +1,164 @@
+package annot
+
+import (
+	"strings"
+	"time"
+)
+
+// ParseLogfmt parses a logfmt line of key=value pairs separated by spaces,
+// e.g. `level=info msg="request done" cached`. Every value is a field
+// called by its key. A key without "=" is a field of the key. It returns
+// a *SyntaxError at the column where the line breaks the syntax, e.g. an
+// unterminated quoted value.
+func ParseLogfmt(line string) ([]Field, error) {
+	var fields []Field
+	i := 0
+	for {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i == len(line) {
+			return fields, nil
+		}
+
+		keyStart := i
+		for i < len(line) && line[i] != ' ' && line[i] != '=' {
+			if line[i] == '"' {
+				return nil, newSyntaxError(displayCol(line, i), "quote in key")
+			}
+			i++
+		}
+		key := line[keyStart:i]
+		if key == "" {
+			return nil, newSyntaxError(displayCol(line, i), "empty key")
+		}
+		if i == len(line) || line[i] == ' ' {
+			fields = append(fields, newField(key, line, keyStart, i))
+			continue
+		}
+
+		i++
+		valueStart := i
+		if i < len(line) && line[i] == '"' {
+			end, ok := quotedEnd(line, i)
+			if !ok {
+				return nil, newSyntaxError(displayCol(line, i), "unterminated quoted value")
+			}
+			i = end
+			if i < len(line) && line[i] != ' ' {
+				return nil, newSyntaxError(displayCol(line, i), "missing space after quoted value")
+			}
+		} else {
+			for i < len(line) && line[i] != ' ' {
+				if line[i] == '"' || line[i] == '=' {
+					return nil, newSyntaxError(displayCol(line, i), "unquoted "+line[i:i+1]+" in value")
+				}
+				i++
+			}
+		}
+		fields = append(fields, newField(key, line, valueStart, i))
+	}
+}
+
+// quotedEnd returns the index after the closing quote of the quoted string
+// starting at the quote at start of s. Quotes escaped by a backslash do
+// not close the string.
+func quotedEnd(s string, start int) (int, bool) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// commonLogTime is the time layout of the common log format.
+const commonLogTime = "02/Jan/2006:15:04:05 -0700"
+
+// ParseCommonLog parses a line of the common log format of the Apache HTTP
+// Server, e.g.
+//
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 2326
+//
+// into fields called "host", "ident", "user", "time", "request", "status"
+// and "size". The fields "time" and "request" do not contain the brackets
+// and quotes. It returns a *SyntaxError at the column where the line
+// breaks the format.
+func ParseCommonLog(line string) ([]Field, error) {
+	var fields []Field
+	i := 0
+	for fIdx, name := range []string{"host", "ident", "user", "time", "request", "status", "size"} {
+		if fIdx > 0 {
+			if i == len(line) {
+				return nil, newSyntaxError(sourceWidth(line), "missing "+name)
+			}
+			if line[i] != ' ' {
+				return nil, newSyntaxError(displayCol(line, i), "missing space before "+name)
+			}
+			i++
+		}
+
+		start, end := i, i
+		switch name {
+		case "time":
+			if i == len(line) || line[i] != '[' {
+				return nil, newSyntaxError(displayCol(line, i), `time needs to start with "["`)
+			}
+			closeIdx := strings.IndexByte(line[i:], ']')
+			if closeIdx == -1 {
+				return nil, newSyntaxError(displayCol(line, i), `missing "]"`)
+			}
+			start, end = i+1, i+closeIdx
+			if _, err := time.Parse(commonLogTime, line[start:end]); err != nil {
+				return nil, newSyntaxError(displayCol(line, start), "time needs to be like "+commonLogTime)
+			}
+			i = end + 1
+		case "request":
+			if i == len(line) || line[i] != '"' {
+				return nil, newSyntaxError(displayCol(line, i), "request needs to be quoted")
+			}
+			quoteEnd, ok := quotedEnd(line, i)
+			if !ok {
+				return nil, newSyntaxError(displayCol(line, i), "unterminated request")
+			}
+			start, end = i+1, quoteEnd-1
+			i = quoteEnd
+		default:
+			for end < len(line) && line[end] != ' ' {
+				end++
+			}
+			if start == end {
+				return nil, newSyntaxError(displayCol(line, start), "empty "+name)
+			}
+			i = end
+		}
+
+		switch text := line[start:end]; {
+		case name == "status" && !isDigits(text, 3):
+			return nil, newSyntaxError(displayCol(line, start), "status needs to be 3 digits")
+		case name == "size" && text != "-" && !isDigits(text, len(text)):
+			return nil, newSyntaxError(displayCol(line, start), `size needs to be digits or "-"`)
+		}
+		fields = append(fields, newField(name, line, start, end))
+	}
+	if i != len(line) {
+		return nil, newSyntaxError(displayCol(line, i), "unexpected text after size")
+	}
+	return fields, nil
+}
+
+// isDigits reports whether s consists of n ASCII digits.
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package annot
+
+import "testing"
+
+func TestParseLogfmt(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "values, quoted value and key without value",
+			line: `level=info msg="req \"done\"" cached dur=`,
+			wantW: `
+level=info msg="req \"done\"" cached dur=
+      └┬─┘     └─────┬──────┘ └─┬──┘     ↑
+       └─ level      └─ msg     │        └─ dur
+                                └─ cached
+`,
+		},
+		{
+			name: "unterminated quoted value",
+			line: `level=info msg="req`,
+			wantW: `
+level=info msg="req
+               ↑
+               └─ unterminated quoted value
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "empty key",
+			line:    `level=info =x`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "quote in unquoted value",
+			line:    `msg=a"b`,
+			wantErr: &SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseLogfmt(tt.line)
+			annots, ok := fieldsOrSyntaxErr(t, err, tt.wantErr, fields...)
+			if !ok || tt.wantW == "" {
+				return
+			}
+			if gotW := "\n" + tt.line + "\n" + String(annots...); gotW != tt.wantW {
+				t.Errorf("ParseLogfmt() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestParseCommonLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "valid",
+			line: `::1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 -`,
+			wantW: `
+::1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 -
+└┬┘ ↑ ↑  └───────────┬────────────┘   └─────┬──────┘  └┬┘ ↑
+ │  │ └─ user        └─ time                │          │  └─ size
+ │  │                                       │          │
+ │  └─ ident                                │          └─ status
+ │                                          └─ request
+ └─ host
+`,
+		},
+		{
+			name: "invalid status",
+			line: `::1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 20 -`,
+			wantW: `
+::1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 20 -
+                                                      ↑
+                                                      └─ status needs to be 3 digits
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "invalid time",
+			line:    `::1 - - [10/10/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 -`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "missing size",
+			line:    `::1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "text after size",
+			line:    `::1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 1 x`,
+			wantErr: &SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseCommonLog(tt.line)
+			annots, ok := fieldsOrSyntaxErr(t, err, tt.wantErr, fields...)
+			if !ok || tt.wantW == "" {
+				return
+			}
+			if gotW := "\n" + tt.line + "\n" + String(annots...); gotW != tt.wantW {
+				t.Errorf("ParseCommonLog() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
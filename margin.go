@@ -0,0 +1,72 @@
+package annot
+
+import "strings"
+
+// WithMarginNotes renders the lines of all annotations in a margin starting
+// at column col. Leader dots connect the stems with the margin, e.g.
+// "└┄┄┄┄ note". If col does not leave room for at least one leader dot, the
+// margin starts right of the rightmost stem.
+func WithMarginNotes(col int) Option {
+	return func(r *Renderer) {
+		r.marginCol = col
+	}
+}
+
+// marginNotes reports whether lines are rendered in a margin.
+func (r *Renderer) marginNotes() bool {
+	return r.marginCol > 0
+}
+
+// setMarginRows stacks the annotations from the rightmost to the leftmost
+// annotation, so that leader dots never cross a stem.
+func setMarginRows(annots []*Annot) {
+	row := 0
+	for aIdxDecr := len(annots) - 1; 0 <= aIdxDecr; aIdxDecr-- {
+		a := annots[aIdxDecr]
+		a.row = row
+		row += len(a.lines)
+	}
+}
+
+// marginColumn returns the column where the margin starts.
+func (r *Renderer) marginColumn(annots []*Annot) int {
+	col := r.marginCol
+	for _, a := range annots {
+		// Corner, leader dot and space in front of the margin.
+		col = max(col, a.pipeColIdx+3)
+	}
+	return col
+}
+
+// marginRows returns the rendered rows of annotations with their lines
+// in a margin.
+func (r *Renderer) marginRows(annots []*Annot) [][]Segment {
+	marginCol := r.marginColumn(annots)
+
+	rowCount := 0
+	for _, a := range annots {
+		rowCount = max(rowCount, a.row+len(a.lines))
+	}
+
+	rows := make([][]Segment, rowCount+1)
+	rows[0] = r.markerRow(annots)
+
+	for row := 0; row < rowCount; row++ {
+		var segments []Segment
+		for _, a := range annots {
+			switch {
+			case row < a.row:
+				segments = append(segments, Segment{Col: a.pipeColIdx, Text: r.pipe(a), Kind: PipeSegment, Annot: a, Index: a.idx})
+			case row == a.row:
+				leader := r.glyphs.Corner + strings.Repeat(r.glyphs.Leader, marginCol-a.pipeColIdx-2) + " "
+				segments = append(segments, Segment{Col: a.pipeColIdx, Text: leader, Kind: ConnectorSegment, Annot: a, Index: a.idx})
+				segments = appendLine(segments, marginCol, a.lines[0], a)
+			case row < a.row+len(a.lines):
+				segments = appendLine(segments, marginCol, a.lines[row-a.row], a)
+			}
+		}
+		rows[row+1] = segments
+	}
+
+	return rows
+}
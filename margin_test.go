@@ -0,0 +1,63 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithMarginNotes(t *testing.T) {
+	tests := []struct {
+		name      string
+		marginCol int
+		annots    []*Annot
+		wantW     string
+		wantWidth int
+	}{
+		{
+			name:      "notes in margin",
+			marginCol: 20,
+			annots: []*Annot{
+				{Col: 1, Lines: []string{"article"}},
+				{Col: 4, ColEnd: 11, Lines: []string{"adjective", "second"}},
+				{Col: 14, Lines: []string{"noun"}},
+			},
+			wantW: `
+ ↑  └──┬───┘  ↑
+ │     │      └┄┄┄┄ noun
+ │     └┄┄┄┄┄┄┄┄┄┄┄ adjective
+ │                  second
+ └┄┄┄┄┄┄┄┄┄┄┄┄┄┄┄┄┄ article
+`,
+			wantWidth: 29,
+		},
+		{
+			name:      "margin starts right of the rightmost stem",
+			marginCol: 1,
+			annots: []*Annot{
+				{Col: 1, Lines: []string{"article"}},
+				{Col: 4, Lines: []string{"x"}},
+			},
+			wantW: `
+ ↑  ↑
+ │  └┄ x
+ └┄┄┄┄ article
+`,
+			wantWidth: 14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(WithMarginNotes(tt.marginCol))
+			w := &bytes.Buffer{}
+			if err := r.Write(w, tt.annots...); err != nil {
+				t.Fatalf("Write() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if gotWidth := r.RequiredWidth(tt.annots...); gotWidth != tt.wantWidth {
+				t.Errorf("RequiredWidth() = %v, want %v", gotWidth, tt.wantWidth)
+			}
+		})
+	}
+}
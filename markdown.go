@@ -0,0 +1,69 @@
+package annot
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WithMarkdownList appends a bullet list of the columns and lines of the
+// annotations to the code block of WriteMarkdown, e.g. "- column 4 → noun",
+// so that annotations stay readable without the drawing.
+func WithMarkdownList() Option {
+	return func(r *Renderer) {
+		r.markdownList = true
+	}
+}
+
+// WriteMarkdown writes line followed by the rendered annotations in a
+// fenced code block to a writer w, e.g. for GitHub issues. See
+// [Renderer.WriteMarkdown].
+func WriteMarkdown(w io.Writer, line string, annots ...*Annot) error {
+	return NewRenderer().WriteMarkdown(w, line, annots...)
+}
+
+// WriteMarkdown writes line followed by the rendered annotations in a
+// fenced code block to a writer w, e.g. for GitHub issues. The fence is
+// longer than every run of backticks in the block. With WithMarkdownList
+// a bullet list of the annotations ordered by their columns follows the
+// code block.
+func (r *Renderer) WriteMarkdown(w io.Writer, line string, annots ...*Annot) error {
+	l, err := r.Layout(annots...)
+	if l == nil {
+		return err
+	}
+	block := line + "\n" + l.String()
+	fence := strings.Repeat("`", max(3, longestBacktickRun(block)+1))
+
+	b := &strings.Builder{}
+	b.WriteString(fence + "\n" + block + fence + "\n")
+	if r.markdownList && len(l.annots) != 0 {
+		b.WriteString("\n")
+		for _, a := range l.annots {
+			text := strings.Join(a.Lines, " ")
+			if a.SeeLine != 0 {
+				text += " " + r.seeLine(a.SeeLine)
+			}
+			fmt.Fprintf(b, "- %s → %s\n", columns(a), strings.TrimSpace(text))
+		}
+	}
+	if _, writeErr := io.WriteString(w, b.String()); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// longestBacktickRun returns the length of the longest run of backticks
+// in s.
+func longestBacktickRun(s string) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != '`' {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return longest
+}
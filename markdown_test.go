@@ -0,0 +1,80 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRenderer_WriteMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		line    string
+		annots  []*Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "code block",
+			line: "The quick fox",
+			annots: []*Annot{
+				{Col: 4, ColEnd: 8, Lines: []string{"adjective"}},
+			},
+			wantW: "```\n" +
+				"The quick fox\n" +
+				"    └─┬─┘\n" +
+				"      └─ adjective\n" +
+				"```\n",
+		},
+		{
+			name: "list",
+			opts: []Option{WithMarkdownList()},
+			line: "The quick fox",
+			annots: []*Annot{
+				{Col: 10, Lines: []string{"noun", "animal"}},
+				{Col: 4, ColEnd: 8, Lines: []string{"adjective"}},
+			},
+			wantW: "```\n" +
+				"The quick fox\n" +
+				"    └─┬─┘ ↑\n" +
+				"      │   └─ noun\n" +
+				"      │      animal\n" +
+				"      │\n" +
+				"      └─ adjective\n" +
+				"```\n" +
+				"\n" +
+				"- columns 4–8 → adjective\n" +
+				"- column 10 → noun animal\n",
+		},
+		{
+			name: "fence longer than backticks",
+			line: "a ```b```",
+			annots: []*Annot{
+				{Col: 2, ColEnd: 8, Lines: []string{"code"}},
+			},
+			wantW: "````\n" +
+				"a ```b```\n" +
+				"  └──┬──┘\n" +
+				"     └─ code\n" +
+				"````\n",
+		},
+		{
+			name:    "invalid annotations",
+			annots:  []*Annot{nil},
+			wantErr: &NilAnnotError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := NewRenderer(tt.opts...).WriteMarkdown(w, tt.line, tt.annots...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("WriteMarkdown() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("WriteMarkdown() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
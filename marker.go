@@ -0,0 +1,56 @@
+package annot
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// ParseMarkers returns an annotation for every marker in markerRow, e.g. a
+// row of carets from another tool. A marker is a run of characters other
+// than spaces. A marker of one column becomes an arrow and a longer marker
+// a range. The annotations have no lines and are ordered by their columns.
+func ParseMarkers(markerRow string) []*Annot {
+	var annots []*Annot
+	var current *Annot
+
+	col := 0
+	g := uniseg.NewGraphemes(markerRow)
+	for g.Next() {
+		width := g.Width()
+		if strings.TrimSpace(g.Str()) == "" {
+			current = nil
+			col += width
+			continue
+		}
+		if current == nil {
+			current = &Annot{Col: col}
+			annots = append(annots, current)
+		}
+		if end := col + width - 1; end > current.Col {
+			current.ColEnd = end
+		}
+		col += width
+	}
+	return annots
+}
+
+// WriteBelow writes markerRow instead of the rendered arrowheads and
+// ranges followed by the stems and lines of the annotations. The columns
+// of the annotations should match the markers in markerRow, e.g. by
+// creating them with ParseMarkers.
+func (r *Renderer) WriteBelow(w io.Writer, markerRow string, annots ...*Annot) error {
+	l, err := r.Layout(annots...)
+	if l == nil {
+		return err
+	}
+	if _, writeErr := fmt.Fprintln(w, markerRow); writeErr != nil {
+		return writeErr
+	}
+	if writeErr := l.writeRows(w, 1); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package annot
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkers(t *testing.T) {
+	tests := []struct {
+		name      string
+		markerRow string
+		want      []*Annot
+	}{
+		{
+			name:      "no markers",
+			markerRow: "   ",
+			want:      nil,
+		},
+		{
+			name:      "arrows and ranges",
+			markerRow: "^  ~~~~ ^^",
+			want: []*Annot{
+				{Col: 0},
+				{Col: 3, ColEnd: 6},
+				{Col: 8, ColEnd: 9},
+			},
+		},
+		{
+			name:      "wide marker",
+			markerRow: " 〜",
+			want: []*Annot{
+				{Col: 1, ColEnd: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMarkers(tt.markerRow); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMarkers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderer_WriteBelow(t *testing.T) {
+	markerRow := "    ^^^^^^   ^"
+	annots := ParseMarkers(markerRow)
+	annots[0].Lines = []string{"range"}
+	annots[1].Lines = []string{"arrow"}
+
+	w := &bytes.Buffer{}
+	if err := NewRenderer().WriteBelow(w, markerRow, annots...); err != nil {
+		t.Fatalf("WriteBelow() unexpected error = %v", err)
+	}
+
+	want := `
+    ^^^^^^   ^
+      │      └─ arrow
+      └─ range
+`
+	if got := "\n" + w.String(); got != want {
+		t.Errorf("WriteBelow() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package annot
+
+// MediaType is a parsed media type like "text/html; charset=utf-8" of a
+// Content-Type header or a media range like "text/*;q=0.8" of an Accept
+// header.
+type MediaType struct {
+	// Type and Subtype are the fields called "type" and "subtype".
+	Type, Subtype Field
+
+	// Params are the parameters in the order of the value.
+	Params []MediaParam
+}
+
+// MediaParam is a parameter of a media type like "charset=utf-8".
+type MediaParam struct {
+	// Name and Value are the fields called "parameter" and "value". A
+	// quoted Value contains the quotes.
+	Name, Value Field
+}
+
+// Fields returns the fields of the media type in the order of the value.
+func (m MediaType) Fields() []Field {
+	fields := []Field{m.Type, m.Subtype}
+	for _, p := range m.Params {
+		fields = append(fields, p.Name, p.Value)
+	}
+	return fields
+}
+
+// ParseMediaType parses the value of a Content-Type header. It returns a
+// *SyntaxError at the column where value breaks the syntax of RFC 9110,
+// e.g. a parameter without "=".
+func ParseMediaType(value string) (MediaType, error) {
+	m, i, err := parseMediaType(value, 0)
+	if err != nil {
+		return MediaType{}, err
+	}
+	if i != len(value) {
+		return MediaType{}, newSyntaxError(displayCol(value, i), `expected ";"`)
+	}
+	return m, nil
+}
+
+// ParseAccept parses the comma separated media ranges of the value of an
+// Accept header like ParseMediaType.
+func ParseAccept(value string) ([]MediaType, error) {
+	var ms []MediaType
+	i := 0
+	for {
+		m, end, err := parseMediaType(value, i)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+		if end == len(value) {
+			return ms, nil
+		}
+		if value[end] != ',' {
+			return nil, newSyntaxError(displayCol(value, end), `expected ";" or ","`)
+		}
+		i = end + 1
+	}
+}
+
+// parseMediaType parses the media type starting at the index i of s. It
+// returns the index after the media type and its trailing white space.
+func parseMediaType(s string, i int) (MediaType, int, error) {
+	var m MediaType
+	i = skipSpace(s, i)
+
+	typeEnd := tokenEnd(s, i)
+	if typeEnd == len(s) || s[typeEnd] != '/' {
+		if err := checkToken(s, i, typeEnd, "type"); err != nil {
+			return MediaType{}, 0, err
+		}
+		return MediaType{}, 0, newSyntaxError(displayCol(s, typeEnd), `missing "/"`)
+	}
+	if err := checkToken(s, i, typeEnd, "type"); err != nil {
+		return MediaType{}, 0, err
+	}
+	m.Type = newField("type", s, i, typeEnd)
+
+	i = typeEnd + 1
+	subtypeEnd := tokenEnd(s, i)
+	if i == subtypeEnd {
+		return MediaType{}, 0, newSyntaxError(displayCol(s, i), "empty subtype")
+	}
+	m.Subtype = newField("subtype", s, i, subtypeEnd)
+
+	i = skipSpace(s, subtypeEnd)
+	for i < len(s) && s[i] == ';' {
+		i = skipSpace(s, i+1)
+		nameEnd := tokenEnd(s, i)
+		if i == nameEnd {
+			return MediaType{}, 0, newSyntaxError(displayCol(s, i), "empty parameter name")
+		}
+		if nameEnd == len(s) || s[nameEnd] != '=' {
+			return MediaType{}, 0, newSyntaxError(displayCol(s, nameEnd), `missing "=" in parameter`)
+		}
+		p := MediaParam{Name: newField("parameter", s, i, nameEnd)}
+
+		i = nameEnd + 1
+		valueEnd := tokenEnd(s, i)
+		if i < len(s) && s[i] == '"' {
+			end, ok := quotedEnd(s, i)
+			if !ok {
+				return MediaType{}, 0, newSyntaxError(displayCol(s, i), "unterminated quoted parameter value")
+			}
+			valueEnd = end
+		}
+		if i == valueEnd {
+			return MediaType{}, 0, newSyntaxError(displayCol(s, i), "empty parameter value")
+		}
+		p.Value = newField("value", s, i, valueEnd)
+		m.Params = append(m.Params, p)
+		i = skipSpace(s, valueEnd)
+	}
+	return m, i, nil
+}
+
+// tokenEnd returns the index after the token characters starting at the
+// index i of s.
+func tokenEnd(s string, i int) int {
+	for i < len(s) && isTokenChar(s[i]) {
+		i++
+	}
+	return i
+}
+
+// skipSpace returns the index of the first byte at or after i of s that
+// is not a space or a tab.
+func skipSpace(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,91 @@
+package annot
+
+import "testing"
+
+func TestParseMediaType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name:  "type, subtype and parameters",
+			value: `text/html; charset=utf-8;x="a;b"`,
+			wantW: `
+text/html; charset=utf-8;x="a;b"
+└┬─┘ └┬─┘  └──┬──┘ └─┬─┘ ↑ └─┬─┘
+ │    │       │      │   │   └─ value
+ │    │       │      │   │
+ │    │       │      │   └─ parameter
+ │    │       │      │
+ │    │       │      └─ value
+ │    │       │
+ │    │       └─ parameter
+ │    └─ subtype
+ └─ type
+`,
+		},
+		{
+			name:  "parameter without equals sign",
+			value: "text/html; charset",
+			wantW: `
+text/html; charset
+                  ↑
+                  └─ missing "=" in parameter
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:  "missing slash",
+			value: "text",
+			wantW: `
+text
+    ↑
+    └─ missing "/"
+`,
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "invalid character in type",
+			value:   "te(xt/html",
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "missing semicolon",
+			value:   "text/html charset=utf-8",
+			wantErr: &SyntaxError{},
+		},
+		{
+			name:    "unterminated quoted value",
+			value:   `text/html; a="b`,
+			wantErr: &SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseMediaType(tt.value)
+			annots, ok := fieldsOrSyntaxErr(t, err, tt.wantErr, m.Fields()...)
+			if !ok || tt.wantW == "" {
+				return
+			}
+			if gotW := "\n" + tt.value + "\n" + String(annots...); gotW != tt.wantW {
+				t.Errorf("ParseMediaType() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestParseAccept(t *testing.T) {
+	ms, err := ParseAccept("text/*;q=0.8, application/json")
+	if err != nil {
+		t.Fatalf("ParseAccept() unexpected error = %v", err)
+	}
+	if len(ms) != 2 || ms[0].Subtype.Text != "*" || ms[1].Type.Text != "application" || ms[1].Type.Col != 14 {
+		t.Errorf("ParseAccept() = %+v, want text/* and application/json", ms)
+	}
+
+	if _, err := ParseAccept("text/html;q=1 x"); err == nil {
+		t.Errorf("ParseAccept() error = nil, want %T", &SyntaxError{})
+	}
+}
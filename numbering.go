@@ -0,0 +1,48 @@
+package annot
+
+// Numbering is a kind of numbers that replace arrowheads and stems of
+// ranges and are put in front of the lines of annotations, so readers can
+// pair markers and lines in dense rows.
+type Numbering int
+
+const (
+	// NumberingNone does not number annotations. It is the default.
+	NumberingNone Numbering = iota
+
+	// NumberingCircled numbers annotations with ① to ⑳.
+	NumberingCircled
+
+	// NumberingDigits numbers annotations with 1 to 9 followed by a to z
+	// and A to Z.
+	NumberingDigits
+)
+
+// digits are the numbers of NumberingDigits.
+const digits = "123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// WithNumbering numbers the annotations in the order of their columns.
+// Annotations after the last number of numbering are not numbered.
+func WithNumbering(numbering Numbering) Option {
+	return func(r *Renderer) {
+		r.numbering = numbering
+	}
+}
+
+// numberLabel returns the number of the arranged annotation a or "" if a
+// is not numbered.
+func (r *Renderer) numberLabel(a *Annot) string {
+	if a.stemOnly || a.num < 1 {
+		return ""
+	}
+	switch r.numbering {
+	case NumberingCircled:
+		if a.num <= 20 {
+			return string(rune('①' + a.num - 1))
+		}
+	case NumberingDigits:
+		if a.num <= len(digits) {
+			return digits[a.num-1 : a.num]
+		}
+	}
+	return ""
+}
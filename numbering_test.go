@@ -0,0 +1,85 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithNumbering(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Lines: []string{"first", "second line"}},
+		{Col: 2, ColEnd: 6, Lines: []string{"range"}},
+		{Col: 8, ColEnd: 9, Lines: []string{"short range"}},
+		{Col: 11},
+	}
+	tests := []struct {
+		name  string
+		opts  []Option
+		wantW string
+	}{
+		{
+			name: "circled",
+			opts: []Option{WithNumbering(NumberingCircled)},
+			wantW: `
+① └─②─┘ ③┘ ④
+│   │   │  └─ ④
+│   │   │
+│   │   └─ ③ short range
+│   │
+│   └─ ② range
+│
+└─ ① first
+     second line
+`,
+		},
+		{
+			name: "digits with wrapped lines",
+			opts: []Option{WithNumbering(NumberingDigits), WithWidth(14)},
+			wantW: `
+1 └─2─┘ 3┘ 4
+│   │   │  └─ 4
+│   │   │
+│   │   └─ 3 short
+│   │        range
+│   │
+│   └─ 2 range
+│
+└─ 1 first
+     second
+     line
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := NewRenderer(tt.opts...).Write(w, annots...); err != nil {
+				t.Fatalf("Write() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestRenderer_numberLabel(t *testing.T) {
+	tests := []struct {
+		numbering Numbering
+		num       int
+		want      string
+	}{
+		{numbering: NumberingNone, num: 1, want: ""},
+		{numbering: NumberingCircled, num: 20, want: "⑳"},
+		{numbering: NumberingCircled, num: 21, want: ""},
+		{numbering: NumberingDigits, num: 10, want: "a"},
+		{numbering: NumberingDigits, num: 61, want: "Z"},
+		{numbering: NumberingDigits, num: 62, want: ""},
+	}
+	for _, tt := range tests {
+		r := NewRenderer(WithNumbering(tt.numbering))
+		if got := r.numberLabel(&Annot{num: tt.num}); got != tt.want {
+			t.Errorf("numberLabel() of %d = %q, want %q", tt.num, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package annot
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Overflow is how WithHardWidth fits rows into the width of WithWidth.
+type Overflow int
+
+const (
+	// overflowNone does not fit rows, so words wider than the available
+	// columns exceed the width.
+	overflowNone Overflow = iota
+
+	// OverflowBreak breaks words of lines that are wider than the
+	// available columns.
+	OverflowBreak
+
+	// OverflowTruncate ends lines that are wider than the available
+	// columns with an ellipsis "…".
+	OverflowTruncate
+)
+
+// WithHardWidth guarantees that no row is wider than the width of
+// WithWidth without the reserved columns, e.g. for bordered panels that
+// clip overflow badly. Lines are fitted as configured by overflow.
+// Arrowheads, ranges and stems right of the width are removed and rows
+// that are still too wide are truncated. With WithStrict, a *ClippedError
+// is returned instead of removing a segment of an annotation. Unknown
+// overflows are ignored.
+func WithHardWidth(overflow Overflow) Option {
+	return func(r *Renderer) {
+		if overflow == OverflowBreak || overflow == OverflowTruncate {
+			r.overflow = overflow
+		}
+	}
+}
+
+// breakSegments returns the line segments with segments wider than width
+// broken into segments of at most width columns.
+func (r *Renderer) breakSegments(segments []lineSegment, width int) []lineSegment {
+	var broken []lineSegment
+	for sIdx, seg := range segments {
+		if seg.trimmedWidth <= width {
+			if broken != nil {
+				broken = append(broken, seg)
+			}
+			continue
+		}
+		if broken == nil {
+			broken = append(make([]lineSegment, 0, len(segments)+1), segments[:sIdx]...)
+		}
+		text := seg.text
+		for text != "" {
+			head := r.cut(text, width)
+			if head == "" {
+				// A grapheme cluster wider than width.
+				head, _, _, _ = uniseg.FirstGraphemeClusterInString(text, -1)
+			}
+			text = text[len(head):]
+			part := lineSegment{
+				text:         head,
+				width:        r.stringWidth(head),
+				trimmedWidth: r.stringWidth(strings.TrimRight(head, " \n\r")),
+			}
+			if strings.TrimRight(text, " \n\r") == "" {
+				part.text += text
+				part.width += r.stringWidth(text)
+				part.mustBreak = seg.mustBreak
+				text = ""
+			}
+			broken = append(broken, part)
+		}
+	}
+	if broken == nil {
+		return segments
+	}
+	return broken
+}
+
+// clip returns the segments of a row with segments right of width removed
+// and the segment crossing width truncated. The second result are the
+// removed segments.
+func (r *Renderer) clip(segments []Segment, width int) ([]Segment, []Segment) {
+	for sIdx, s := range segments {
+		if s.Col >= width {
+			return segments[:sIdx], segments[sIdx:]
+		}
+		if s.Col+r.stringWidth(s.Text) > width {
+			clipped := append(segments[:sIdx:sIdx], s)
+			clipped[sIdx].Text = r.truncate(s.Text, width-s.Col)
+			return clipped, segments[sIdx+1:]
+		}
+	}
+	return segments, nil
+}
+
+// clipRows clips the rows to width. A strict Renderer returns a
+// *ClippedError instead if a segment of an annotation would be removed.
+func (r *Renderer) clipRows(rows [][]Segment, width int) ([][]Segment, error) {
+	clippedRows := make([][]Segment, len(rows))
+	for row, segments := range rows {
+		clipped, removed := r.clip(segments, width)
+		if r.strict {
+			for _, s := range removed {
+				if s.Annot != nil {
+					return nil, newClippedError(s.Index+1, s.Col, width)
+				}
+			}
+		}
+		clippedRows[row] = clipped
+	}
+	return clippedRows, nil
+}
+
+// truncate returns s cut to width columns and ended with an ellipsis if s
+// is wider than width.
+func (r *Renderer) truncate(s string, width int) string {
+	if r.stringWidth(s) <= width {
+		return s
+	}
+	ellipsis := r.glyphs.Ellipsis
+	ellipsisWidth := r.stringWidth(ellipsis)
+	if width < ellipsisWidth {
+		return r.cut(s, width)
+	}
+	return r.cut(s, width-ellipsisWidth) + ellipsis
+}
+
+// cut returns the longest prefix of s of whole grapheme clusters with a
+// display width of at most width.
+func (r *Renderer) cut(s string, width int) string {
+	w := 0
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		gw := r.stringWidth(g.Str())
+		if w+gw > width {
+			start, _ := g.Positions()
+			return s[:start]
+		}
+		w += gw
+	}
+	return s
+}
@@ -0,0 +1,123 @@
+package annot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithHardWidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		annots []*Annot
+		wantW  string
+	}{
+		{
+			name: "words exceed width without hard width",
+			opts: []Option{WithWidth(12)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a supercalifragilistic word"}},
+			},
+			wantW: `
+↑
+└─ a
+   supercalifragilistic
+   word
+`,
+		},
+		{
+			name: "break",
+			opts: []Option{WithWidth(12), WithHardWidth(OverflowBreak)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a supercalifragilistic word"}},
+			},
+			wantW: `
+↑
+└─ a
+   supercali
+   fragilist
+   ic word
+`,
+		},
+		{
+			name: "truncate",
+			opts: []Option{WithWidth(12), WithHardWidth(OverflowTruncate)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a supercalifragilistic word"}},
+			},
+			wantW: `
+↑
+└─ a
+   supercal…
+   word
+`,
+		},
+		{
+			name: "stems right of width",
+			opts: []Option{WithWidth(8), WithHardWidth(OverflowTruncate)},
+			annots: []*Annot{
+				{Col: 2, ColEnd: 8, Lines: []string{"range"}},
+				{Col: 10, Lines: []string{"x"}},
+			},
+			wantW: "\n" +
+				"  └──┬─…\n" +
+				"     │\n" +
+				"     │\n" +
+				"     └─ \n",
+		},
+		{
+			name: "reserved columns",
+			opts: []Option{WithWidth(12), WithReserveLeft(2, nil), WithReserveRight(2), WithHardWidth(OverflowTruncate)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"abcdefghijkl"}},
+			},
+			wantW: `
+  ↑
+  └─ abcd…
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(tt.opts...)
+			if gotW := "\n" + r.String(tt.annots...); gotW != tt.wantW {
+				t.Errorf("String() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestWithHardWidth_strict(t *testing.T) {
+	r := NewRenderer(WithStrict(), WithWidth(10), WithHardWidth(OverflowTruncate))
+	l, err := r.Layout(&Annot{Col: 30, Lines: []string{"x"}})
+	if !errors.Is(err, newClippedError(1, 30, 10)) {
+		t.Fatalf("Layout() error = %v, want %v", err, newClippedError(1, 30, 10))
+	}
+	if l != nil {
+		t.Errorf("Layout() got = %v, want nil", l)
+	}
+
+	l, err = r.Layout(&Annot{Col: 2, Lines: []string{"x"}})
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+	if err := l.Reflow(2); !errors.Is(err, newClippedError(1, 2, 2)) {
+		t.Errorf("Reflow() error = %v, want %v", err, newClippedError(1, 2, 2))
+	}
+	if got, want := l.String(), "  ↑\n  └─ x\n"; got != want {
+		t.Errorf("String() after Reflow() got = %q, want %q", got, want)
+	}
+}
+
+func TestRenderer_breakSegments(t *testing.T) {
+	r := NewRenderer()
+	segments := r.segments("ab abcdefg 世界世界 ")
+	var got []string
+	for _, seg := range r.breakSegments(segments, 3) {
+		got = append(got, seg.text)
+	}
+	if want := []string{"ab ", "abc", "def", "g ", "世", "界", "世", "界 "}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("breakSegments() = %q, want %q", got, want)
+	}
+}
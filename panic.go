@@ -0,0 +1,123 @@
+package annot
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// panicFrameFunc matches the function line of a stack frame.
+	panicFrameFunc = regexp.MustCompile(`^(\S+)\(.*\)$`)
+
+	// panicFrameFile matches the file line of a stack frame.
+	panicFrameFile = regexp.MustCompile(`^\t(.+):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// ParsePanic parses the stack trace of the panicking goroutine in the
+// output of a crashed Go program, e.g.
+//
+//	panic: runtime error: index out of range [5] with length 3
+//
+//	goroutine 1 [running]:
+//	main.get(...)
+//		/home/gopher/main.go:8
+//	main.main()
+//		/home/gopher/main.go:12 +0x1d
+//
+// The first n frames outside of the runtime become diagnostics with the
+// function name as message. All frames are returned if n is 0. The first
+// frame is an error with the panic message as annotation. The other frames
+// are infos with an annotation at the call of the function of the previous
+// frame.
+//
+// The source lines are read from fsys. A leading slash of absolute paths
+// is removed, so os.DirFS("/") reads the files of a stack trace of the
+// same machine. Source and the columns keep the tabs of the line like in
+// ParseCompilerOutput. If fsys is nil or a file cannot be read, the
+// diagnostic has no source line and no annotation.
+func ParsePanic(r io.Reader, fsys fs.FS, n int) ([]*Diagnostic, error) {
+	sources := newSourceCache(fsys)
+
+	var diags []*Diagnostic
+	message := ""
+	inStack := false
+	prevFunc := ""
+	fn := ""
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		text := strings.TrimSuffix(s.Text(), "\r")
+
+		if !inStack {
+			for _, prefix := range []string{"panic: ", "fatal error: "} {
+				if rest, ok := strings.CutPrefix(text, prefix); ok && message == "" {
+					message = rest
+				}
+			}
+			inStack = strings.HasPrefix(text, "goroutine ") && strings.HasSuffix(text, ":")
+			continue
+		}
+
+		if text == "" || (n > 0 && len(diags) == n) {
+			break
+		}
+		if m := panicFrameFunc.FindStringSubmatch(text); m != nil {
+			fn = m[1]
+			continue
+		}
+		m := panicFrameFile.FindStringSubmatch(text)
+		if m == nil || fn == "" || strings.HasPrefix(fn, "runtime.") || fn == "panic" {
+			fn = ""
+			continue
+		}
+
+		lineNum, _ := strconv.Atoi(m[2])
+		d := &Diagnostic{File: m[1], Line: lineNum, Severity: SeverityInfo, Message: fn}
+		if len(diags) == 0 {
+			d.Severity = SeverityError
+		}
+		if source, ok := sources.line(d.File, d.Line); ok {
+			d.Source = source
+			var a *Annot
+			if len(diags) == 0 {
+				a = lineAnnot(source)
+				if a != nil && message != "" {
+					a.Lines = []string{message}
+				}
+			} else {
+				a = callAnnot(source, prevFunc)
+				if a != nil {
+					a.Lines = []string{"calls " + prevFunc}
+				}
+			}
+			if a != nil {
+				d.Annots = []*Annot{a}
+			}
+		}
+		diags = append(diags, d)
+		prevFunc = fn
+		fn = ""
+	}
+	return diags, s.Err()
+}
+
+// callAnnot returns an annotation of the last call of the function fn in
+// source or of the whole line if the call is not found. The columns are
+// the columns of source in which a tab counts as one column.
+func callAnnot(source, fn string) *Annot {
+	name := fn[strings.LastIndex(fn, ".")+1:]
+	idx := strings.LastIndex(source, name+"(")
+	if name == "" || idx == -1 {
+		return lineAnnot(source)
+	}
+	col := byteColToSourceCol(source, idx)
+	a := &Annot{Col: col}
+	if colEnd := byteColToSourceCol(source, idx+len(name)) - 1; colEnd > col {
+		a.ColEnd = colEnd
+	}
+	return a
+}
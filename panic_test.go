@@ -0,0 +1,143 @@
+package annot
+
+import (
+	"bytes"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParsePanic(t *testing.T) {
+	fsys := fstest.MapFS{
+		"home/gopher/main.go": {Data: []byte("package main\n\nfunc get(s []int) int {\n\treturn s[5]\n}\n\nfunc main() {\n\tfmt.Println(get(nil))\n}\n")},
+	}
+	output := `panic: runtime error: index out of range [5] with length 0
+
+goroutine 1 [running]:
+panic({0x4a1f20, 0xc000012345})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+runtime.goPanicIndex(0x5, 0x0)
+	/usr/local/go/src/runtime/panic.go:114 +0x6e
+main.get(...)
+	/home/gopher/main.go:4
+main.main()
+	/home/gopher/main.go:8 +0x1d
+exit status 2
+`
+
+	tests := []struct {
+		name string
+		fsys fs.FS
+		n    int
+		want []*Diagnostic
+	}{
+		{
+			name: "all frames",
+			fsys: fsys,
+			n:    0,
+			want: []*Diagnostic{
+				{
+					File: "/home/gopher/main.go", Line: 4, Source: "\treturn s[5]",
+					Severity: SeverityError, Message: "main.get",
+					Annots: []*Annot{{Col: 1, ColEnd: 11, Lines: []string{"runtime error: index out of range [5] with length 0"}}},
+				},
+				{
+					File: "/home/gopher/main.go", Line: 8, Source: "\tfmt.Println(get(nil))",
+					Severity: SeverityInfo, Message: "main.main",
+					Annots: []*Annot{{Col: 13, ColEnd: 15, Lines: []string{"calls main.get"}}},
+				},
+			},
+		},
+		{
+			name: "top frame",
+			fsys: fsys,
+			n:    1,
+			want: []*Diagnostic{
+				{
+					File: "/home/gopher/main.go", Line: 4, Source: "\treturn s[5]",
+					Severity: SeverityError, Message: "main.get",
+					Annots: []*Annot{{Col: 1, ColEnd: 11, Lines: []string{"runtime error: index out of range [5] with length 0"}}},
+				},
+			},
+		},
+		{
+			name: "without file system",
+			fsys: nil,
+			n:    0,
+			want: []*Diagnostic{
+				{File: "/home/gopher/main.go", Line: 4, Severity: SeverityError, Message: "main.get"},
+				{File: "/home/gopher/main.go", Line: 8, Severity: SeverityInfo, Message: "main.main"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePanic(strings.NewReader(output), tt.fsys, tt.n)
+			if err != nil {
+				t.Fatalf("ParsePanic() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePanic() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallAnnot(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		fn     string
+		want   *Annot
+	}{
+		{name: "function", source: "x := get(1)", fn: "main.get", want: &Annot{Col: 5, ColEnd: 7}},
+		{name: "method", source: "t.Run(get)", fn: "main.(*T).Run", want: &Annot{Col: 2, ColEnd: 4}},
+		{name: "last call", source: "f(f(1))", fn: "main.f", want: &Annot{Col: 2}},
+		{name: "call not found", source: "\tgo func() {}()", fn: "main.main.func1", want: &Annot{Col: 1, ColEnd: 14}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := callAnnot(tt.source, tt.fn); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("callAnnot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePanic_tabs(t *testing.T) {
+	fsys := fstest.MapFS{"main.go": {Data: []byte("package main\n\tpanic(1)\n")}}
+	output := "panic: 1\n\ngoroutine 1 [running]:\nmain.main()\n\t/main.go:2 +0x1d\n"
+	diags, err := ParsePanic(strings.NewReader(output), fsys, 0)
+	if err != nil {
+		t.Fatalf("ParsePanic() unexpected error = %v", err)
+	}
+
+	w := &bytes.Buffer{}
+	if err := WriteGitHubActions(w, diags...); err != nil {
+		t.Fatalf("WriteGitHubActions() unexpected error = %v", err)
+	}
+	wantActions := "::error file=/main.go,line=2,col=2,endColumn=9::main.main%0A1\n"
+	if got := w.String(); got != wantActions {
+		t.Errorf("WriteGitHubActions() got = %q, want %q", got, wantActions)
+	}
+
+	w.Reset()
+	if err := WriteReport(w, diags...); err != nil {
+		t.Fatalf("WriteReport() unexpected error = %v", err)
+	}
+	want := `
+/main.go: 1 error
+
+error: main.main
+2 │         panic(1)
+  │         └──┬───┘
+  │            └─ 1
+
+1 problem (1 error)
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("WriteReport() gotW = %v, want %v", gotW, want)
+	}
+}
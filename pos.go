@@ -0,0 +1,78 @@
+package annot
+
+import (
+	"go/token"
+	"text/scanner"
+)
+
+// Pos is a position in a text with a line and a column. Origin is the
+// number of the first line and the first column, e.g. 1 for the positions
+// of compilers and 0 for the positions of LSP.
+type Pos struct {
+	Line   int
+	Col    int
+	Origin int
+}
+
+// ScannerPos converts a position of text/scanner. Its column counts
+// characters, which equals the display column for lines without wide
+// characters.
+func ScannerPos(p scanner.Position) Pos {
+	return Pos{Line: p.Line, Col: p.Column, Origin: 1}
+}
+
+// TokenPos converts a position of go/token. Its column counts bytes, which
+// equals the display column for ASCII lines.
+func TokenPos(p token.Position) Pos {
+	return Pos{Line: p.Line, Col: p.Column, Origin: 1}
+}
+
+// LSPPos converts a position of the Language Server Protocol. Its
+// character offset counts UTF-16 code units by default, which equals the
+// display column for ASCII lines.
+func LSPPos(line, character int) Pos {
+	return Pos{Line: line, Col: character, Origin: 0}
+}
+
+// LineColumner is a position with a 1-based line and column, e.g. a
+// position of a parser generated by participle or goyacc wrapped in a
+// small adapter type.
+type LineColumner interface {
+	Line() int
+	Column() int
+}
+
+// PosOf converts a position with a 1-based line and column.
+func PosOf(p LineColumner) Pos {
+	return Pos{Line: p.Line(), Col: p.Column(), Origin: 1}
+}
+
+// LineIndex returns the line of p counted from 0.
+func (p Pos) LineIndex() int {
+	return p.Line - p.Origin
+}
+
+// ColIndex returns the column of p counted from 0.
+func (p Pos) ColIndex() int {
+	return p.Col - p.Origin
+}
+
+// Annot returns an annotation with lines at the column of p. Line and Col
+// of the annotation are LineIndex and ColIndex, i.e. counted from 0 like
+// the lines and columns of WriteDoc with the default origin, so the
+// annotation is written to the line of p by WriteDoc.
+func (p Pos) Annot(lines ...string) *Annot {
+	return &Annot{Col: p.ColIndex(), Line: p.LineIndex(), Lines: lines}
+}
+
+// AnnotTo returns an annotation with lines for the range from p to the
+// exclusive end position end in the same line, e.g. the end of a token.
+// If the range covers only one column, an arrow is annotated. Line is set
+// like in Annot.
+func (p Pos) AnnotTo(end Pos, lines ...string) *Annot {
+	a := p.Annot(lines...)
+	if colEnd := end.ColIndex() - 1; colEnd > a.Col {
+		a.ColEnd = colEnd
+	}
+	return a
+}
@@ -0,0 +1,118 @@
+package annot
+
+import (
+	"bytes"
+	"go/token"
+	"reflect"
+	"testing"
+	"text/scanner"
+)
+
+type lineColumn struct {
+	line, column int
+}
+
+func (p lineColumn) Line() int {
+	return p.line
+}
+
+func (p lineColumn) Column() int {
+	return p.column
+}
+
+func TestPos_conversions(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  Pos
+		want Pos
+	}{
+		{
+			name: "text/scanner",
+			pos:  ScannerPos(scanner.Position{Filename: "f", Offset: 12, Line: 2, Column: 5}),
+			want: Pos{Line: 2, Col: 5, Origin: 1},
+		},
+		{
+			name: "go/token",
+			pos:  TokenPos(token.Position{Filename: "f.go", Offset: 30, Line: 3, Column: 1}),
+			want: Pos{Line: 3, Col: 1, Origin: 1},
+		},
+		{
+			name: "LineColumner",
+			pos:  PosOf(lineColumn{line: 7, column: 2}),
+			want: Pos{Line: 7, Col: 2, Origin: 1},
+		},
+		{
+			name: "LSP",
+			pos:  LSPPos(0, 7),
+			want: Pos{Line: 0, Col: 7, Origin: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.pos != tt.want {
+				t.Errorf("got = %v, want %v", tt.pos, tt.want)
+			}
+		})
+	}
+}
+
+func TestPos_Annot(t *testing.T) {
+	p := Pos{Line: 4, Col: 3, Origin: 1}
+	if got := p.LineIndex(); got != 3 {
+		t.Errorf("LineIndex() = %v, want 3", got)
+	}
+	want := &Annot{Col: 2, Line: 3, Lines: []string{"here"}}
+	if got := p.Annot("here"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Annot() = %v, want %v", got, want)
+	}
+}
+
+func TestPos_AnnotTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		start Pos
+		end   Pos
+		want  *Annot
+	}{
+		{
+			name:  "range",
+			start: Pos{Line: 1, Col: 5, Origin: 1},
+			end:   Pos{Line: 1, Col: 9, Origin: 1},
+			want:  &Annot{Col: 4, ColEnd: 7, Line: 0, Lines: []string{"token"}},
+		},
+		{
+			name:  "one column",
+			start: LSPPos(2, 2),
+			end:   LSPPos(2, 3),
+			want:  &Annot{Col: 2, Line: 2, Lines: []string{"token"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.start.AnnotTo(tt.end, "token"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnnotTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPos_Annot_writeDoc(t *testing.T) {
+	text := "port = 8080\nhost = localhost\n"
+	start := Pos{Line: 2, Col: 8, Origin: 1}
+	end := Pos{Line: 2, Col: 17, Origin: 1}
+	w := &bytes.Buffer{}
+	if err := WriteDoc(w, text, start.AnnotTo(end, "host"), LSPPos(0, 7).Annot("port")); err != nil {
+		t.Fatalf("WriteDoc() unexpected error = %v", err)
+	}
+	want := `
+port = 8080
+       ↑
+       └─ port
+host = localhost
+       └───┬───┘
+           └─ host
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("WriteDoc() gotW = %v, want %v", gotW, want)
+	}
+}
@@ -0,0 +1,33 @@
+package annot
+
+// Push is a decision of the layout to push the lines of an annotation to
+// a lower row, because they do not fit left of the annotations to the
+// right.
+type Push struct {
+	// Annot is the annotation whose lines are pushed.
+	Annot *Annot
+
+	// Row is the row the lines do not fit in. The row of the connector
+	// below the first row is 0.
+	Row int
+
+	// Tight reports whether the lines fit in Row with the spacing of
+	// WithGap(1).
+	Tight bool
+}
+
+// tightSpacing returns the spacing of WithGap(1) pushes can be vetoed with.
+func tightSpacing() spacing {
+	return spacing{above: 1, lineTwo: 0, linesAfterSecond: 1, trailingSpaceLines: 0}
+}
+
+// WithPushHook calls fn every time the layout is about to push the lines
+// of an annotation to a lower row, e.g. to log layout decisions. If fn
+// returns false for a Tight push, the push is vetoed and the lines stay in
+// the row with the spacing of WithGap(1). Other pushes cannot be vetoed.
+// fn is not called for layouts taken from the cache of WithLayoutCache.
+func WithPushHook(fn func(p Push) bool) Option {
+	return func(r *Renderer) {
+		r.pushHook = fn
+	}
+}
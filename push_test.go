@@ -0,0 +1,71 @@
+package annot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithPushHook(t *testing.T) {
+	tests := []struct {
+		name       string
+		col        int
+		veto       bool
+		wantW      string
+		wantPushes []Push
+	}{
+		{
+			name: "accept",
+			col:  9,
+			wantW: `
+↑        ↑
+│        └─ line2
+└─ line1
+`,
+			wantPushes: []Push{{Row: 0, Tight: true}},
+		},
+		{
+			name: "veto",
+			col:  9,
+			veto: true,
+			wantW: `
+↑        ↑
+└─ line1 └─ line2
+`,
+			wantPushes: []Push{{Row: 0, Tight: true}},
+		},
+		{
+			name: "veto of push that is not tight",
+			col:  8,
+			veto: true,
+			wantW: `
+↑       ↑
+│       └─ line2
+└─ line1
+`,
+			wantPushes: []Push{{Row: 0, Tight: false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as := []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: tt.col, Lines: []string{"line2"}},
+			}
+			var pushes []Push
+			r := NewRenderer(WithPushHook(func(p Push) bool {
+				if p.Annot != as[0] {
+					t.Errorf("Push.Annot = %v, want %v", p.Annot, as[0])
+				}
+				p.Annot = nil
+				pushes = append(pushes, p)
+				return !tt.veto
+			}))
+			if gotW := "\n" + r.String(as...); gotW != tt.wantW {
+				t.Errorf("String() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if !reflect.DeepEqual(pushes, tt.wantPushes) {
+				t.Errorf("pushes = %v, want %v", pushes, tt.wantPushes)
+			}
+		})
+	}
+}
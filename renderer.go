@@ -0,0 +1,277 @@
+package annot
+
+import (
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Renderer renders annotations configured by options. The package level
+// functions String and Write use a Renderer without options. The package
+// has no mutable package level state, so Renderers never interfere with
+// each other. A Renderer is safe for concurrent use, annotations are not:
+// rendering stores the layout in unexported fields of the annotations.
+type Renderer struct {
+	noArrowheads bool
+	connector    string
+	connWidth    int
+	marginCol    int
+	widths       map[string]int
+	strict       bool
+	skipInvalid  bool
+	width        int
+	collapse     int
+	origin       int
+	dupWindow    int
+	cache        *layoutCache
+	blockWrite   bool
+	canonical    bool
+	compat       CompatLevel
+	styles       Styles
+	gap          int
+	glyphs       GlyphSet
+	numbering    Numbering
+	summary      bool
+	color        colorMode
+	flowMin      int
+	gridCell     int
+	pushHook     func(p Push) bool
+	reserveLeft  int
+	fillLeft     func(row int) string
+	markdownList bool
+	reserveRight int
+	overflow     Overflow
+	parallelMin  int
+	linear       bool
+	gapMin       int
+
+	severityStyles     map[Severity]Style
+	severityArrowheads map[Severity]string
+	severityStacking   bool
+}
+
+// Option configures a Renderer.
+type Option func(r *Renderer)
+
+// NewRenderer returns a Renderer configured with opts.
+func NewRenderer(opts ...Option) *Renderer {
+	r := &Renderer{glyphs: DefaultGlyphs(), parallelMin: parallelMinAnnots}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.canonical {
+		r.noArrowheads = false
+		r.glyphs = DefaultGlyphs()
+		r.connector = ""
+		r.styles = Styles{}
+		r.color = colorNever
+	}
+	if r.connector == "" {
+		r.connector = r.glyphs.Connector
+	}
+	r.connWidth = r.stringWidth(r.connector)
+	return r
+}
+
+// WithoutArrowheads starts the stems of annotations with a pipe │
+// instead of an arrowhead ↑. Ranges are drawn unchanged.
+func WithoutArrowheads() Option {
+	return func(r *Renderer) {
+		r.noArrowheads = true
+	}
+}
+
+// WithConnector replaces the connector "└─ " between a stem and the first
+// line of an annotation, e.g. with "└──▶ " or "+- ". The first character
+// of connector is drawn in the column of the stem and lines are indented
+// by the display width of connector. An empty connector is ignored.
+func WithConnector(connector string) Option {
+	return func(r *Renderer) {
+		if connector != "" {
+			r.connector = connector
+		}
+	}
+}
+
+// WithCanonical renders byte-stable output for golden files and snapshot
+// tests. The default glyphs without styles are used regardless of other
+// options, trailing whitespace of rows is removed and rows end with "\n".
+// Output rendered with WithCanonical only changes in a new major version
+// of annot.
+func WithCanonical() Option {
+	return func(r *Renderer) {
+		r.canonical = true
+	}
+}
+
+// WithStrict returns an error instead of silently dropping annotations,
+// e.g. an annotation with the same column as another annotation or an
+// annotation right of the width of WithHardWidth. Lines collapsed by
+// WithCollapse are not an error.
+func WithStrict() Option {
+	return func(r *Renderer) {
+		r.strict = true
+	}
+}
+
+// WithOrigin sets the column origin of Col and ColEnd, e.g. 1 for the
+// 1-based columns of editors and compilers. The default origin is 0.
+// Annotations with a Col lower than origin are invalid.
+func WithOrigin(origin int) Option {
+	return func(r *Renderer) {
+		r.origin = origin
+	}
+}
+
+// WithGap sets the minimum number of blank columns between the lines of
+// an annotation and the stems and lines of annotations to the right. The
+// default gap is 2. A gap lower than 1 is ignored.
+func WithGap(gap int) Option {
+	return func(r *Renderer) {
+		r.gap = gap
+	}
+}
+
+// WithBlockWrite writes all rendered rows with a single call of Write
+// instead of one call per row, e.g. to minimize syscalls when writing to
+// files and pipes. Writers that buffer themselves, like *bufio.Writer,
+// *bytes.Buffer and *strings.Builder, are always written per row.
+func WithBlockWrite() Option {
+	return func(r *Renderer) {
+		r.blockWrite = true
+	}
+}
+
+// WithDuplicateWindow treats annotations whose stems are at most n columns
+// apart as duplicates, e.g. near-identical positions of noisy generators.
+// Like annotations with the same column, only the first of them is
+// rendered or, with WithStrict, a *DuplicateColError is returned. The
+// stem of a range is in its middle.
+func WithDuplicateWindow(n int) Option {
+	return func(r *Renderer) {
+		r.dupWindow = n
+	}
+}
+
+// WithSkipInvalid renders the valid annotations and skips invalid ones,
+// e.g. overlapping annotations, instead of rendering nothing. Write
+// returns a *SkippedError that reports every skipped annotation.
+func WithSkipInvalid() Option {
+	return func(r *Renderer) {
+		r.skipInvalid = true
+	}
+}
+
+// WithWidth limits rows to width columns by wrapping the lines of
+// annotations. A line is wrapped at the smaller width of MaxWidth and the
+// columns left of width. Words wider than the available columns are not
+// broken.
+func WithWidth(width int) Option {
+	return func(r *Renderer) {
+		r.width = width
+	}
+}
+
+// WithFlow wraps the lines of an annotation left of the stem of the next
+// annotation, so that paragraphs of neighboring annotations are rendered
+// side by side instead of below each other. Lines are only wrapped this
+// way if at least minWidth columns are available, otherwise they are
+// wrapped like without WithFlow. A minWidth lower than 1 is 1.
+func WithFlow(minWidth int) Option {
+	return func(r *Renderer) {
+		r.flowMin = max(minWidth, 1)
+	}
+}
+
+// WithCollapse renders only the first n lines of annotations with more
+// than n lines followed by a line "… (+K more lines)". Layout.Collapsed
+// returns the collapsed annotations, so their full text can be shown
+// separately. Collapsing lines is not an error with WithStrict.
+func WithCollapse(n int) Option {
+	return func(r *Renderer) {
+		r.collapse = n
+	}
+}
+
+// WithWidths overrides the measured display width of grapheme clusters,
+// e.g. of flags or ZWJ emoji sequences whose width differs between
+// terminals. The keys of widths are single grapheme clusters.
+func WithWidths(widths map[string]int) Option {
+	return func(r *Renderer) {
+		r.widths = maps.Clone(widths)
+	}
+}
+
+// stringWidth returns the display width of s with the overridden widths
+// of grapheme clusters.
+func (r *Renderer) stringWidth(s string) int {
+	if len(r.widths) == 0 {
+		return uniseg.StringWidth(s)
+	}
+
+	width := 0
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		if w, ok := r.widths[g.Str()]; ok {
+			width += w
+			continue
+		}
+		width += g.Width()
+	}
+	return width
+}
+
+// DisplayWidth returns the display width of s like annot measures the
+// lines of annotations. See [Renderer.DisplayWidth].
+func DisplayWidth(s string) int {
+	return NewRenderer().DisplayWidth(s)
+}
+
+// DisplayWidth returns the display width of s like the Renderer measures
+// the lines of annotations, e.g. to align output around rendered
+// annotations. Widths of WithWidths are taken into account.
+func (r *Renderer) DisplayWidth(s string) int {
+	return r.stringWidth(s)
+}
+
+// Truncate returns s cut to width columns. See [Renderer.Truncate].
+func Truncate(s string, width int) string {
+	return NewRenderer().Truncate(s, width)
+}
+
+// Truncate returns s cut to width columns like the Renderer truncates
+// rows with WithHardWidth. If s is wider than width, it is cut between
+// grapheme clusters and ended with the Ellipsis glyph.
+func (r *Renderer) Truncate(s string, width int) string {
+	return r.truncate(s, max(width, 0))
+}
+
+// Render renders the annotations and writes them to a writer w. It is the
+// same as Write.
+func (r *Renderer) Render(w io.Writer, annots ...*Annot) error {
+	return r.Write(w, annots...)
+}
+
+// String returns the rendered annotations as a string.
+func (r *Renderer) String(annots ...*Annot) string {
+	b := &strings.Builder{}
+	_ = r.Write(b, annots...)
+	return b.String()
+}
+
+// MustString is like String but panics if the annotations are invalid,
+// including annotations skipped by WithSkipInvalid.
+func (r *Renderer) MustString(annots ...*Annot) string {
+	b := &strings.Builder{}
+	r.MustWrite(b, annots...)
+	return b.String()
+}
+
+// MustWrite is like Write but panics if Write returns an error.
+func (r *Renderer) MustWrite(w io.Writer, annots ...*Annot) {
+	if err := r.Write(w, annots...); err != nil {
+		panic(err)
+	}
+}
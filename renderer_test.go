@@ -0,0 +1,410 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRenderer_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		annots  []*Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "without options",
+			annots: []*Annot{
+				{Col: 1, Lines: []string{"line1"}},
+			},
+			wantW: `
+ ↑
+ └─ line1
+`,
+		},
+		{
+			name: "without arrowheads",
+			opts: []Option{WithoutArrowheads()},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 2, ColEnd: 4, Lines: []string{"line1"}},
+				{Col: 7, Lines: []string{"line1"}},
+			},
+			wantW: `
+│ └┬┘  │
+│  │   └─ line1
+│  │
+│  └─ line1
+│
+└─ line1
+`,
+		},
+		{
+			name: "wide connector",
+			opts: []Option{WithConnector("└──▶ ")},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1", "line2"}},
+				{Col: 8, Lines: []string{"line1", "line2", "line3"}},
+				{Col: 12, Lines: []string{"line1", "line2"}},
+			},
+			wantW: `
+↑       ↑   ↑
+│       │   └──▶ line1
+│       │        line2
+│       │
+│       └──▶ line1
+│            line2
+└──▶ line1   line3
+     line2
+`,
+		},
+		{
+			name: "narrow connector",
+			opts: []Option{WithConnector("└")},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1", "line2"}},
+				{Col: 8, Lines: []string{"line1", "line2", "line3"}},
+			},
+			wantW: `
+↑       ↑
+└line1  └line1
+ line2   line2
+         line3
+`,
+		},
+		{
+			name: "empty connector is ignored",
+			opts: []Option{WithConnector("")},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+			},
+			wantW: `
+↑
+└─ line1
+`,
+		},
+		{
+			name: "overridden width of ZWJ emoji sequence",
+			opts: []Option{WithWidths(map[string]int{"👨‍👩‍👧": 6})},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"👨‍👩‍👧"}},
+				{Col: 8, Lines: []string{"x"}},
+			},
+			wantW: `
+↑       ↑
+│       └─ x
+└─ 👨‍👩‍👧
+`,
+		},
+		{
+			name: "measured width of ZWJ emoji sequence",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"👨‍👩‍👧"}},
+				{Col: 8, Lines: []string{"x"}},
+			},
+			wantW: `
+↑       ↑
+└─ 👨‍👩‍👧   └─ x
+`,
+		},
+		{
+			name: "strict with same column",
+			opts: []Option{WithStrict()},
+			annots: []*Annot{
+				{Col: 3, Lines: []string{"line1"}},
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 3, Lines: []string{"same column position"}},
+			},
+			wantErr: &DuplicateColError{},
+		},
+		{
+			name: "strict without same column",
+			opts: []Option{WithStrict()},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 10, Lines: []string{"line1"}},
+			},
+			wantW: `
+↑         ↑
+└─ line1  └─ line1
+`,
+		},
+		{
+			name: "limit width",
+			opts: []Option{WithWidth(14)},
+			annots: []*Annot{
+				{Col: 2, Lines: []string{"wrapped at width"}},
+				{Col: 8, MaxWidth: 3, Lines: []string{"max width"}},
+			},
+			wantW: `
+  ↑     ↑
+  │     └─ max
+  │        width
+  │
+  └─ wrapped
+     at width
+`,
+		},
+		{
+			name: "one-based columns",
+			opts: []Option{WithOrigin(1)},
+			annots: []*Annot{
+				{Col: 1, Lines: []string{"first"}},
+				{Col: 5, ColEnd: 7, Lines: []string{"range"}},
+			},
+			wantW: `
+↑   └┬┘
+│    └─ range
+│
+└─ first
+`,
+		},
+		{
+			name: "duplicate window",
+			opts: []Option{WithDuplicateWindow(2)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"first"}},
+				{Col: 2, Lines: []string{"within window"}},
+				{Col: 5, ColEnd: 9, Lines: []string{"range"}},
+				{Col: 9, Lines: []string{"near stem of range"}},
+				{Col: 11, Lines: []string{"outside window"}},
+			},
+			wantW: `
+↑    └─┬─┘ ↑
+│      │   └─ outside window
+│      │
+│      └─ range
+└─ first
+`,
+		},
+		{
+			name: "strict duplicate window",
+			opts: []Option{WithStrict(), WithDuplicateWindow(1)},
+			annots: []*Annot{
+				{Col: 3, Lines: []string{"line1"}},
+				{Col: 4, Lines: []string{"line1"}},
+			},
+			wantErr: &DuplicateColError{},
+		},
+		{
+			name: "gap",
+			opts: []Option{WithGap(4)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 10, Lines: []string{"line1", "line2"}},
+				{Col: 12, Lines: []string{"line1"}},
+			},
+			wantW: `
+↑         ↑ ↑
+│         │ └─ line1
+│         │
+│         └─ line1
+│            line2
+└─ line1
+`,
+		},
+		{
+			name: "gap of 1",
+			opts: []Option{WithGap(1)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"line1"}},
+				{Col: 9, Lines: []string{"line1"}},
+			},
+			wantW: `
+↑        ↑
+└─ line1 └─ line1
+`,
+		},
+		{
+			name: "flow",
+			opts: []Option{WithFlow(8)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"a paragraph that wraps left of the next stem"}},
+				{Col: 20, Lines: []string{"wraps only at the width"}},
+				{Col: 22, Lines: []string{"no room"}},
+			},
+			wantW: `
+↑                   ↑ ↑
+└─ a paragraph      │ └─ no room
+   that wraps left  │
+   of the next      └─ wraps only at the width
+   stem
+`,
+		},
+		{
+			name: "canonical",
+			opts: []Option{WithConnector("+- "), WithoutArrowheads(), WithCanonical()},
+			annots: []*Annot{
+				{Col: 0},
+				{Col: 4, Lines: []string{"trailing space ", "crlf\r"}},
+			},
+			wantW: "\n" +
+				"↑   ↑\n" +
+				"│   └─ trailing space\n" +
+				"└─     crlf\n",
+		},
+		{
+			name: "column lower than origin",
+			opts: []Option{WithOrigin(1)},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"first"}},
+			},
+			wantErr: &ColOutOfRangeError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := NewRenderer(tt.opts...).Write(w, tt.annots...)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestRenderer_stringWidth(t *testing.T) {
+	r := NewRenderer(WithWidths(map[string]int{"🇩🇪": 4, "a": 0}))
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{s: "", want: 0},
+		{s: "bc", want: 2},
+		{s: "a🇩🇪", want: 4},
+		{s: "🇩🇪🇫🇷", want: 6},
+	}
+	for _, tt := range tests {
+		if got := r.stringWidth(tt.s); got != tt.want {
+			t.Errorf("stringWidth(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestRenderer_Truncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		s     string
+		width int
+		want  string
+	}{
+		{name: "fits", s: "abc", width: 3, want: "abc"},
+		{name: "ellipsis", s: "abcdef", width: 4, want: "abc…"},
+		{name: "wide characters", s: "世界世界", width: 6, want: "世界…"},
+		{name: "ascii ellipsis", opts: []Option{WithTheme(ThemeASCII)}, s: "abcdef", width: 5, want: "ab..."},
+		{name: "narrower than ellipsis", opts: []Option{WithTheme(ThemeASCII)}, s: "abcdef", width: 2, want: "ab"},
+		{name: "overridden width", opts: []Option{WithWidths(map[string]int{"🇩🇪": 4})}, s: "🇩🇪a", width: 4, want: "…"},
+		{name: "negative width", s: "abc", width: -1, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(tt.opts...)
+			got := r.Truncate(tt.s, tt.width)
+			if got != tt.want {
+				t.Errorf("Truncate() = %q, want %q", got, tt.want)
+			}
+			if r.DisplayWidth(got) > max(tt.width, 0) {
+				t.Errorf("DisplayWidth(%q) = %v, want at most %v", got, r.DisplayWidth(got), tt.width)
+			}
+		})
+	}
+	if got := DisplayWidth("a世界"); got != 5 {
+		t.Errorf("DisplayWidth() = %v, want 5", got)
+	}
+	if got := Truncate("a世界", 4); got != "a世…" {
+		t.Errorf("Truncate() = %q, want %q", got, "a世…")
+	}
+}
+
+func TestWithSkipInvalid(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, ColEnd: 3, Lines: []string{"range"}},
+		{Col: 2, Lines: []string{"overlaps"}},
+		{Col: 6, ColEnd: 6, Lines: []string{"col equals col end"}},
+		{Col: 8, Lines: []string{"arrow"}},
+	}
+
+	w := &bytes.Buffer{}
+	err := NewRenderer(WithSkipInvalid()).Write(w, annots...)
+
+	wantW := `
+└┬─┘    ↑
+ │      └─ arrow
+ └─ range
+`
+	if gotW := "\n" + w.String(); gotW != wantW {
+		t.Errorf("Write() gotW = %v, want %v", gotW, wantW)
+	}
+
+	var skippedErr *SkippedError
+	if !errors.As(err, &skippedErr) {
+		t.Fatalf("Write() error = %v, want %T", err, skippedErr)
+	}
+	skipped := skippedErr.Skipped()
+	if len(skipped) != 2 {
+		t.Fatalf("Skipped() len = %v, want 2", len(skipped))
+	}
+	if skipped[0].Annot != annots[1] || !errors.Is(skipped[0].Err, &OverlapError{}) {
+		t.Errorf("Skipped()[0] = %v, want overlapping annotation", skipped[0])
+	}
+	if skipped[1].Annot != annots[2] || !errors.Is(skipped[1].Err, &ColExceedsColEndError{}) {
+		t.Errorf("Skipped()[1] = %v, want annotation with col equal to col end", skipped[1])
+	}
+	if !errors.Is(err, &OverlapError{}) {
+		t.Errorf("Write() error = %v, want it to wrap %T", err, &OverlapError{})
+	}
+}
+
+func TestRenderer_concurrent(t *testing.T) {
+	annots := func() []*Annot {
+		return []*Annot{
+			{Col: 0, Lines: []string{"line1"}},
+			{Col: 4, ColEnd: 8, Lines: []string{"line2", "line3"}},
+		}
+	}
+	renderers := []*Renderer{
+		NewRenderer(),
+		NewRenderer(WithTheme(ThemeASCII)),
+		NewRenderer(WithGap(4), WithNumbering(NumberingDigits)),
+		NewRenderer(WithStyles(Styles{Text: Bold}), WithForceColor()),
+	}
+	want := make([]string, len(renderers))
+	for rIdx, r := range renderers {
+		want[rIdx] = r.String(annots()...)
+	}
+
+	got := make([][]string, len(renderers))
+	wg := sync.WaitGroup{}
+	for rIdx, r := range renderers {
+		got[rIdx] = make([]string, 50)
+		for i := range got[rIdx] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got[rIdx][i] = r.String(annots()...)
+			}()
+		}
+	}
+	wg.Wait()
+
+	for rIdx := range renderers {
+		for _, g := range got[rIdx] {
+			if g != want[rIdx] {
+				t.Fatalf("String() of renderer %d = %q, want %q", rIdx, g, want[rIdx])
+			}
+		}
+	}
+	if got := String(annots()...); got != want[0] {
+		t.Errorf("String() = %q after other renderers, want %q", got, want[0])
+	}
+}
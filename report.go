@@ -0,0 +1,130 @@
+package annot
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WriteReport renders the diagnostics grouped by file and writes them to a
+// writer w. See [Renderer.WriteReport] for the format of the report.
+func WriteReport(w io.Writer, diags ...*Diagnostic) error {
+	return NewRenderer().WriteReport(w, diags...)
+}
+
+// WriteReport renders the diagnostics grouped by file and writes them to a
+// writer w. Files are ordered by their first diagnostic and diagnostics of
+// a file are ordered from the highest to the lowest severity. Every file
+// starts with a header with the counts of its severities and the report
+// ends with a summary of all diagnostics. Nothing is written if a
+// diagnostic is nil.
+func (r *Renderer) WriteReport(w io.Writer, diags ...*Diagnostic) error {
+	r = r.coloredFor(w)
+	var files []string
+	byFile := map[string][]*Diagnostic{}
+	gutterWidth := 0
+	for dIdx, d := range diags {
+		if d == nil {
+			return newNilDiagnosticError(dIdx + 1)
+		}
+		if _, ok := byFile[d.File]; !ok {
+			files = append(files, d.File)
+		}
+		byFile[d.File] = append(byFile[d.File], d)
+		gutterWidth = max(gutterWidth, lineNumWidth(d.Line))
+	}
+
+	b := &strings.Builder{}
+	for _, file := range files {
+		fileDiags := byFile[file]
+		slices.SortStableFunc(fileDiags, func(a, b *Diagnostic) int {
+			return int(b.Severity - a.Severity)
+		})
+
+		if file != "" {
+			b.WriteString(file)
+			b.WriteString(": ")
+			b.WriteString(severityCounts(fileDiags))
+			b.WriteString("\n\n")
+		}
+
+		for _, d := range fileDiags {
+			if d.Severity != SeverityNone {
+				b.WriteString(d.Severity.String())
+				b.WriteString(": ")
+			}
+			b.WriteString(d.Message)
+			b.WriteString("\n")
+
+			if source, annots := r.expandSource(d.Source, d.annots()); source != "" || len(annots) != 0 {
+				err := r.writeEntry(b, gutterWidth, Entry{LineNum: d.Line, Line: source, Annots: annots})
+				if err != nil {
+					return err
+				}
+			}
+			if len(d.Fixes) != 0 {
+				if err := r.writeFixes(b, gutterWidth, d.Source, d.Fixes); err != nil {
+					return err
+				}
+			}
+			b.WriteString("\n")
+
+			_, err := fmt.Fprint(w, b.String())
+			if err != nil {
+				return err
+			}
+			b.Reset()
+		}
+	}
+
+	problems := "problems"
+	if len(diags) == 1 {
+		problems = "problem"
+	}
+	b.WriteString(strconv.Itoa(len(diags)))
+	b.WriteString(" ")
+	b.WriteString(problems)
+	if len(diags) != 0 {
+		b.WriteString(" (")
+		b.WriteString(severityCounts(diags))
+		b.WriteString(")")
+	}
+	b.WriteString("\n")
+
+	_, err := fmt.Fprint(w, b.String())
+	return err
+}
+
+// severityCounts returns the counts of the severities of diags from the
+// highest to the lowest severity, e.g. "2 errors, 1 warning".
+func severityCounts(diags []*Diagnostic) string {
+	counts := map[Severity]int{}
+	for _, d := range diags {
+		counts[d.Severity]++
+	}
+	return joinCounts(counts)
+}
+
+// joinCounts returns counts by severity from the highest to the lowest
+// severity, e.g. "2 errors, 1 warning".
+func joinCounts(counts map[Severity]int) string {
+	unknown := 0
+	for s, n := range counts {
+		if s < SeverityNone || SeverityError < s {
+			unknown += n
+		}
+	}
+	var parts []string
+	for s := SeverityError; s >= SeverityNone; s-- {
+		n := counts[s]
+		if s == SeverityNone {
+			n += unknown
+		}
+		if n != 0 {
+			parts = append(parts, s.plural(n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,160 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		diags   []*Diagnostic
+		wantW   string
+		wantErr error
+	}{
+		{
+			name:  "no diagnostics",
+			diags: nil,
+			wantW: `
+0 problems
+`,
+		},
+		{
+			name: "one diagnostic without file",
+			diags: []*Diagnostic{
+				{Line: 3, Source: "x := 1", Severity: SeverityWarning, Message: "unused variable", Annots: []*Annot{{Col: 0, Lines: []string{"x"}}}},
+			},
+			wantW: `
+warning: unused variable
+3 │ x := 1
+  │ ↑
+  │ └─ x
+
+1 problem (1 warning)
+`,
+		},
+		{
+			name: "grouped by file and ordered by severity",
+			diags: []*Diagnostic{
+				{File: "a.go", Line: 9, Source: "a b", Severity: SeverityHint, Message: "simplify", Annots: []*Annot{{Col: 2, Lines: []string{"b"}}}},
+				{File: "b.go", Line: 1, Source: "c", Severity: SeverityInfo, Message: "style"},
+				{File: "a.go", Line: 12, Source: "c d", Severity: SeverityError, Message: "undefined", Annots: []*Annot{{Col: 0, Lines: []string{"c"}}}},
+				{File: "a.go", Severity: SeverityError, Message: "no package"},
+			},
+			wantW: `
+a.go: 2 errors, 1 hint
+
+error: undefined
+12 │ c d
+   │ ↑
+   │ └─ c
+
+error: no package
+
+hint: simplify
+ 9 │ a b
+   │   ↑
+   │   └─ b
+
+b.go: 1 info
+
+info: style
+ 1 │ c
+
+4 problems (2 errors, 1 info, 1 hint)
+`,
+		},
+		{
+			name: "related annotations",
+			diags: []*Diagnostic{
+				{
+					Line: 2, Source: "var x int = \"a\"", Severity: SeverityError, Message: "mismatched types",
+					Annots:  []*Annot{{Col: 12, ColEnd: 14, Lines: []string{"string"}}},
+					Related: []*Annot{{Col: 6, ColEnd: 10, Lines: []string{"expected due to this"}}},
+				},
+			},
+			wantW: `
+error: mismatched types
+2 │ var x int = "a"
+  │       └┄┬┄┘ └┬┘
+  │         ┆    └─ string
+  │         ┆
+  │         └─ expected due to this
+
+1 problem (1 error)
+`,
+		},
+		{
+			name: "fixes",
+			diags: []*Diagnostic{
+				{
+					Line: 4, Source: "x := foo(a,b)", Severity: SeverityHint, Message: "missing space",
+					Annots: []*Annot{{Col: 11, Lines: []string{"after comma"}}},
+					Fixes:  []Fix{{Col: 11, Replacement: " "}, {Col: 0, Width: 1, Replacement: "y"}},
+				},
+			},
+			wantW: `
+hint: missing space
+4 │ x := foo(a,b)
+  │            ↑
+  │            └─ after comma
+  - x := foo(a,b)
+  + y := foo(a, b)
+
+1 problem (1 hint)
+`,
+		},
+		{
+			name: "overlapping fixes",
+			diags: []*Diagnostic{
+				{Line: 1, Source: "abc", Fixes: []Fix{{Col: 0, Width: 2}, {Col: 1, Width: 1}}},
+			},
+			wantErr: &FixError{},
+		},
+		{
+			name: "without severity",
+			diags: []*Diagnostic{
+				{Message: "a"},
+				{Message: "b"},
+			},
+			wantW: `
+a
+
+b
+
+2 problems (2 notes)
+`,
+		},
+		{
+			name: "annotation error",
+			diags: []*Diagnostic{
+				{Line: 1, Source: "a", Annots: []*Annot{{Col: 2, ColEnd: 1}}},
+			},
+			wantErr: &ColExceedsColEndError{},
+		},
+		{
+			name:    "nil diagnostic",
+			diags:   []*Diagnostic{{Message: "a"}, nil},
+			wantErr: &NilDiagnosticError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := WriteReport(w, tt.diags...)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("WriteReport() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteReport() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteReport() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
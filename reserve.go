@@ -0,0 +1,58 @@
+package annot
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// WithReserveLeft reserves the first n columns of every rendered row, e.g.
+// for gutters, timestamps or severity badges. The reserved columns are
+// blank if fill is nil, otherwise they start with fill(row), where row 0
+// is the row of arrowheads and ranges. Text of fill wider than n columns
+// is cut. Columns of segments of a Layout do not include the reserved
+// columns, the width of WithWidth does.
+func WithReserveLeft(n int, fill func(row int) string) Option {
+	return func(r *Renderer) {
+		r.reserveLeft = max(n, 0)
+		r.fillLeft = fill
+	}
+}
+
+// WithReserveRight reserves the last n columns of the width of WithWidth,
+// e.g. for the border of a panel. Rows are limited to the columns left of
+// the reserved columns.
+func WithReserveRight(n int) Option {
+	return func(r *Renderer) {
+		r.reserveRight = max(n, 0)
+	}
+}
+
+// available returns the columns of width between the reserved columns or
+// 0 if width does not limit rows.
+func (r *Renderer) available(width int) int {
+	if width <= 0 {
+		return 0
+	}
+	return max(width-r.reserveLeft-r.reserveRight, 1)
+}
+
+// appendReserved appends the reserved columns of a row to dst.
+func (r *Renderer) appendReserved(dst []byte, row int) []byte {
+	if r.reserveLeft == 0 {
+		return dst
+	}
+	width := 0
+	if r.fillLeft != nil {
+		g := uniseg.NewGraphemes(r.fillLeft(row))
+		for g.Next() {
+			w := r.stringWidth(g.Str())
+			if width+w > r.reserveLeft {
+				break
+			}
+			dst = append(dst, g.Str()...)
+			width += w
+		}
+	}
+	return append(dst, strings.Repeat(" ", r.reserveLeft-width)...)
+}
@@ -0,0 +1,77 @@
+package annot
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestWithReserveLeft(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Lines: []string{"line1"}},
+		{Col: 2, Lines: []string{"line2"}},
+	}
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantW     string
+		wantWidth int
+	}{
+		{
+			name: "blank",
+			opts: []Option{WithReserveLeft(2, nil)},
+			wantW: `
+  ↑ ↑
+  │ └─ line2
+  │
+  └─ line1
+`,
+			wantWidth: 12,
+		},
+		{
+			name: "filled",
+			opts: []Option{WithReserveLeft(3, func(row int) string {
+				return strconv.Itoa(row * 5)
+			})},
+			wantW: `
+0  ↑ ↑
+5  │ └─ line2
+10 │
+15 └─ line1
+`,
+			wantWidth: 13,
+		},
+		{
+			name: "wide fill is cut",
+			opts: []Option{WithReserveLeft(2, func(int) string { return "🚧🚧" })},
+			wantW: `
+🚧↑ ↑
+🚧│ └─ line2
+🚧│
+🚧└─ line1
+`,
+			wantWidth: 12,
+		},
+		{
+			name: "width includes reserved columns",
+			opts: []Option{WithReserveLeft(4, nil), WithWidth(12)},
+			wantW: `
+    ↑ ↑
+    │ └─ line2
+    │
+    └─ line1
+`,
+			wantWidth: 14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(tt.opts...)
+			if gotW := "\n" + r.String(annots...); gotW != tt.wantW {
+				t.Errorf("String() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if gotWidth := r.RequiredWidth(annots...); gotWidth != tt.wantWidth {
+				t.Errorf("RequiredWidth() = %v, want %v", gotWidth, tt.wantWidth)
+			}
+		})
+	}
+}
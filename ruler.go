@@ -0,0 +1,135 @@
+package annot
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Ruler is a numeric axis scaled to a number of columns, e.g. 0 to 100 in
+// 41 columns. Annotations of values are placed at the columns the values
+// map to.
+//
+//	0         25        50        75      100
+//	├─────────┼─────────┼─────────┼─────────┤
+//	                       ↑
+//	                       └─ threshold
+type Ruler struct {
+	// Min and Max are the values of the first and the last column.
+	Min, Max float64
+
+	// Width is the number of columns of the ruler.
+	Width int
+
+	// Ticks is the number of intervals between labeled ticks. A Ticks
+	// of 0 or less labels only Min and Max.
+	Ticks int
+
+	// Format formats the labels of the ticks. A nil Format formats
+	// values with the fewest digits necessary.
+	Format func(v float64) string
+}
+
+// Col returns the column of the value v. Values outside of Min and Max
+// are mapped to the first or the last column.
+func (ru Ruler) Col(v float64) int {
+	if ru.Width <= 1 || ru.Max == ru.Min {
+		return 0
+	}
+	col := math.Round((v - ru.Min) / (ru.Max - ru.Min) * float64(ru.Width-1))
+	return int(min(max(col, 0), float64(ru.Width-1)))
+}
+
+// Annot returns an annotation with lines at the column of the value v.
+func (ru Ruler) Annot(v float64, lines ...string) *Annot {
+	return &Annot{Col: ru.Col(v), Lines: lines}
+}
+
+// AnnotRange returns an annotation with lines for the columns of the
+// values from to to. If both values map to the same column, an arrow is
+// annotated.
+func (ru Ruler) AnnotRange(from, to float64, lines ...string) *Annot {
+	a := ru.Annot(from, lines...)
+	if colEnd := ru.Col(to); colEnd > a.Col {
+		a.ColEnd = colEnd
+	}
+	return a
+}
+
+// String returns the labels of the ticks and the ruler line, each
+// followed by a newline. The label of Max ends in the last column. Labels
+// between Min and Max that would touch another label are left out.
+func (ru Ruler) String() string {
+	return ru.draw(DefaultGlyphs())
+}
+
+// draw returns the labels of the ticks and the ruler line drawn with the
+// glyphs g like String.
+func (ru Ruler) draw(g GlyphSet) string {
+	if ru.Width <= 0 {
+		return ""
+	}
+	ticks := max(ru.Ticks, 1)
+
+	line := make([]string, ru.Width)
+	for col := range line {
+		line[col] = g.Horizontal
+	}
+	labels := &strings.Builder{}
+	labelsEnd := 0
+	lastLabel := ru.format(ru.Max)
+	lastLabelCol := max(ru.Width-uniseg.StringWidth(lastLabel), 0)
+	for tick := 0; tick <= ticks; tick++ {
+		v := ru.Min + (ru.Max-ru.Min)*float64(tick)/float64(ticks)
+		col := ru.Col(v)
+		switch {
+		case col == 0:
+			line[col] = g.Tee
+		case col == ru.Width-1:
+			line[col] = g.RightTee
+		default:
+			line[col] = g.Crossing
+		}
+
+		label := ru.format(v)
+		labelWidth := uniseg.StringWidth(label)
+		labelCol := col
+		switch {
+		case tick == ticks:
+			label, labelCol = lastLabel, max(lastLabelCol, labelsEnd+1)
+			labelWidth = uniseg.StringWidth(label)
+		case tick != 0 && (labelCol <= labelsEnd || labelCol+labelWidth >= lastLabelCol):
+			continue
+		}
+		labels.WriteString(strings.Repeat(" ", labelCol-labelsEnd))
+		labels.WriteString(label)
+		labelsEnd = labelCol + labelWidth
+	}
+	return labels.String() + "\n" + strings.Join(line, "") + "\n"
+}
+
+func (ru Ruler) format(v float64) string {
+	if ru.Format == nil {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ru.Format(v)
+}
+
+// WriteRuler writes the ruler ru and the rendered annotations below it to
+// a writer w.
+func WriteRuler(w io.Writer, ru Ruler, annots ...*Annot) error {
+	return NewRenderer().WriteRuler(w, ru, annots...)
+}
+
+// WriteRuler writes the ruler ru drawn with the glyphs of the Renderer and
+// the rendered annotations below it to a writer w.
+func (r *Renderer) WriteRuler(w io.Writer, ru Ruler, annots ...*Annot) error {
+	if _, err := fmt.Fprint(w, ru.draw(r.glyphs)); err != nil {
+		return err
+	}
+	return r.Write(w, annots...)
+}
@@ -0,0 +1,107 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRuler_Col(t *testing.T) {
+	ru := Ruler{Min: 0, Max: 100, Width: 41}
+	tests := []struct {
+		name string
+		v    float64
+		want int
+	}{
+		{name: "min", v: 0, want: 0},
+		{name: "max", v: 100, want: 40},
+		{name: "rounded", v: 51, want: 20},
+		{name: "below min", v: -5, want: 0},
+		{name: "above max", v: 120, want: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ru.Col(tt.v); got != tt.want {
+				t.Errorf("Col() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRuler(t *testing.T) {
+	tests := []struct {
+		name   string
+		ru     Ruler
+		annots func(ru Ruler) []*Annot
+		wantW  string
+	}{
+		{
+			name: "ticks and annotations",
+			ru:   Ruler{Min: 0, Max: 100, Width: 41, Ticks: 4},
+			annots: func(ru Ruler) []*Annot {
+				return []*Annot{
+					ru.Annot(57.5, "threshold"),
+					ru.AnnotRange(10, 30, "warm-up"),
+				}
+			},
+			wantW: `
+0         25        50        75      100
+├─────────┼─────────┼─────────┼─────────┤
+    └───┬───┘          ↑
+        └─ warm-up     └─ threshold
+`,
+		},
+		{
+			name: "overlapping labels are left out",
+			ru:   Ruler{Min: 0, Max: 1000, Width: 9, Ticks: 4},
+			annots: func(Ruler) []*Annot {
+				return nil
+			},
+			wantW: `
+0    1000
+├─┼─┼─┼─┤
+`,
+		},
+		{
+			name: "negative values without ticks",
+			ru:   Ruler{Min: -1.5, Max: 1.5, Width: 10},
+			annots: func(ru Ruler) []*Annot {
+				return []*Annot{ru.Annot(0, "zero")}
+			},
+			wantW: `
+-1.5   1.5
+├────────┤
+     ↑
+     └─ zero
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := WriteRuler(w, tt.ru, tt.annots(tt.ru)...); err != nil {
+				t.Fatalf("WriteRuler() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteRuler() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestRenderer_WriteRuler_theme(t *testing.T) {
+	ru := Ruler{Min: 0, Max: 100, Width: 21, Ticks: 2}
+	w := &bytes.Buffer{}
+	err := NewRenderer(WithTheme(ThemeASCII)).WriteRuler(w, ru, ru.Annot(50, "half"))
+	if err != nil {
+		t.Fatalf("WriteRuler() unexpected error = %v", err)
+	}
+	want := `
+0         50      100
++---------+---------+
+          ^
+          ` + "`" + `-- half
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("WriteRuler() gotW = %v, want %v", gotW, want)
+	}
+}
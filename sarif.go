@@ -0,0 +1,217 @@
+package annot
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations,omitempty"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation,omitempty"`
+	Region           *sarifRegion           `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int           `json:"startLine,omitempty"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	EndColumn   int           `json:"endColumn,omitempty"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+// WriteSARIF writes the diagnostics as a SARIF 2.1.0 log of a tool called
+// toolName to a writer w, e.g. for GitHub code scanning. Every annotation
+// of a diagnostic becomes a location with the lines of the annotation as
+// message, related annotations become related locations. The display
+// columns of the annotations are converted to 1-based Unicode code point
+// columns of the source line. Nothing is written if a diagnostic or an
+// annotation is nil.
+func WriteSARIF(w io.Writer, toolName string, diags ...*Diagnostic) error {
+	results := make([]sarifResult, 0, len(diags))
+	for dIdx, d := range diags {
+		if d == nil {
+			return newNilDiagnosticError(dIdx + 1)
+		}
+		for aIdx, a := range d.annots() {
+			if a == nil {
+				return newNilAnnotError(aIdx + 1)
+			}
+		}
+		results = append(results, sarifResultOf(d))
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool:       sarifTool{Driver: sarifDriver{Name: toolName}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+func sarifResultOf(d *Diagnostic) sarifResult {
+	result := sarifResult{
+		Level:   sarifLevel(d.Severity),
+		Message: sarifMessage{Text: d.Message},
+	}
+
+	var artifact *sarifArtifactLocation
+	if d.File != "" {
+		artifact = &sarifArtifactLocation{URI: d.File}
+	}
+	var snippet *sarifMessage
+	if d.Source != "" {
+		snippet = &sarifMessage{Text: d.Source}
+	}
+
+	if len(d.Annots) == 0 {
+		if artifact == nil && d.Line == 0 {
+			return result
+		}
+		loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact}}
+		if d.Line != 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: d.Line, Snippet: snippet}
+		}
+		result.Locations = []sarifLocation{loc}
+		return result
+	}
+
+	for _, a := range d.Annots {
+		result.Locations = append(result.Locations, sarifLocationOf(d, a, artifact, snippet))
+	}
+	for _, a := range d.Related {
+		result.RelatedLocations = append(result.RelatedLocations, sarifLocationOf(d, a, artifact, snippet))
+	}
+	return result
+}
+
+// sarifLocationOf returns the location of the annotation a of d.
+func sarifLocationOf(d *Diagnostic, a *Annot, artifact *sarifArtifactLocation, snippet *sarifMessage) sarifLocation {
+	start, end := runeSpan(d.Source, a.Col, max(a.Col, a.ColEnd))
+	loc := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: artifact,
+			Region: &sarifRegion{
+				StartLine:   d.Line,
+				StartColumn: start + 1,
+				EndColumn:   end + 1,
+				Snippet:     snippet,
+			},
+		},
+	}
+	if len(a.Lines) != 0 {
+		loc.Message = &sarifMessage{Text: strings.Join(a.Lines, "\n")}
+	}
+	return loc
+}
+
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo, SeverityHint:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+// runeSpan returns the 0-based code point index of the display column col
+// of source and the exclusive code point index after the display column
+// colEnd. Columns after the end of source count one code point each.
+func runeSpan(source string, col, colEnd int) (int, int) {
+	return sourceSpan(source, col, colEnd, utf8.RuneCountInString)
+}
+
+// byteSpan is like runeSpan but returns byte indices.
+func byteSpan(source string, col, colEnd int) (int, int) {
+	return sourceSpan(source, col, colEnd, func(s string) int { return len(s) })
+}
+
+// sourceSpan returns the indices of runeSpan in the units counted by count.
+// A tab counts as one column like in the columns of parsed diagnostics.
+func sourceSpan(source string, col, colEnd int, count func(string) int) (int, int) {
+	start, end := -1, -1
+	width, units := 0, 0
+	state := -1
+	rest := source
+	for rest != "" {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if cluster == "\t" {
+			w = 1
+		}
+		clusterUnits := count(cluster)
+		if start == -1 && col < width+w {
+			start = units
+		}
+		if end == -1 && colEnd < width+w {
+			end = units + clusterUnits
+		}
+		width += w
+		units += clusterUnits
+	}
+	if start == -1 {
+		start = units + col - width
+	}
+	if end == -1 {
+		end = units + colEnd - width + 1
+	}
+	return start, end
+}
@@ -0,0 +1,229 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	tests := []struct {
+		name  string
+		diags []*Diagnostic
+		wantW string
+	}{
+		{
+			name:  "no diagnostics",
+			diags: nil,
+			wantW: `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "lint"
+        }
+      },
+      "columnKind": "unicodeCodePoints",
+      "results": []
+    }
+  ]
+}
+`,
+		},
+		{
+			name: "annotations and diagnostic without annotations",
+			diags: []*Diagnostic{
+				{
+					File:     "main.go",
+					Line:     3,
+					Source:   "漢 := 1",
+					Severity: SeverityError,
+					Message:  "bad",
+					Annots: []*Annot{
+						{Col: 0, ColEnd: 1, Lines: []string{"wide", "name"}},
+						{Col: 5},
+					},
+				},
+				{File: "main.go", Severity: SeverityHint, Message: "hint"},
+			},
+			wantW: `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "lint"
+        }
+      },
+      "columnKind": "unicodeCodePoints",
+      "results": [
+        {
+          "level": "error",
+          "message": {
+            "text": "bad"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "main.go"
+                },
+                "region": {
+                  "startLine": 3,
+                  "startColumn": 1,
+                  "endColumn": 2,
+                  "snippet": {
+                    "text": "漢 := 1"
+                  }
+                }
+              },
+              "message": {
+                "text": "wide\nname"
+              }
+            },
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "main.go"
+                },
+                "region": {
+                  "startLine": 3,
+                  "startColumn": 5,
+                  "endColumn": 6,
+                  "snippet": {
+                    "text": "漢 := 1"
+                  }
+                }
+              }
+            }
+          ]
+        },
+        {
+          "level": "note",
+          "message": {
+            "text": "hint"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "main.go"
+                }
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := WriteSARIF(w, "lint", tt.diags...); err != nil {
+				t.Fatalf("WriteSARIF() unexpected error = %v", err)
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("WriteSARIF() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestWriteSARIF_nil(t *testing.T) {
+	tests := []struct {
+		name    string
+		diags   []*Diagnostic
+		wantErr error
+	}{
+		{
+			name:    "nil diagnostic",
+			diags:   []*Diagnostic{nil},
+			wantErr: &NilDiagnosticError{},
+		},
+		{
+			name:    "nil annotation",
+			diags:   []*Diagnostic{{Line: 1, Source: "a", Annots: []*Annot{{Col: 0}, nil}}},
+			wantErr: &NilAnnotError{},
+		},
+		{
+			name:    "nil related annotation",
+			diags:   []*Diagnostic{{Line: 1, Source: "a", Related: []*Annot{nil}}},
+			wantErr: &NilAnnotError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := WriteSARIF(w, "lint", tt.diags...)
+			if !errors.Is(tt.wantErr, err) {
+				t.Errorf("WriteSARIF() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if w.Len() != 0 {
+				t.Errorf("WriteSARIF() gotW = %v, want nothing", w.String())
+			}
+		})
+	}
+}
+
+func TestRuneSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		col       int
+		colEnd    int
+		wantStart int
+		wantEnd   int
+	}{
+		{name: "ascii", source: "abc", col: 1, colEnd: 2, wantStart: 1, wantEnd: 3},
+		{name: "wide character", source: "漢字x", col: 2, colEnd: 4, wantStart: 1, wantEnd: 3},
+		{name: "second column of wide character", source: "漢x", col: 1, colEnd: 1, wantStart: 0, wantEnd: 1},
+		{name: "combining character", source: "e\u0301x", col: 1, colEnd: 1, wantStart: 2, wantEnd: 3},
+		{name: "after end of source", source: "ab", col: 3, colEnd: 4, wantStart: 3, wantEnd: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd := runeSpan(tt.source, tt.col, tt.colEnd)
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("runeSpan() = %v, %v, want %v, %v", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestSarifResultOf_related(t *testing.T) {
+	d := &Diagnostic{
+		Line:    1,
+		Source:  "a = b",
+		Annots:  []*Annot{{Col: 4, Lines: []string{"used"}}},
+		Related: []*Annot{{Col: 0, Lines: []string{"declared"}}},
+	}
+	result := sarifResultOf(d)
+	if len(result.Locations) != 1 || len(result.RelatedLocations) != 1 {
+		t.Fatalf("sarifResultOf() = %d locations and %d related locations, want 1 and 1",
+			len(result.Locations), len(result.RelatedLocations))
+	}
+	related := result.RelatedLocations[0]
+	if related.PhysicalLocation.Region.StartColumn != 1 || related.Message.Text != "declared" {
+		t.Errorf("sarifResultOf() related location = %+v, want column 1 with message declared", related)
+	}
+}
+
+func TestWriteSARIF_tabs(t *testing.T) {
+	w := &bytes.Buffer{}
+	if err := WriteSARIF(w, "go", tabDiagnostics(t)...); err != nil {
+		t.Fatalf("WriteSARIF() unexpected error = %v", err)
+	}
+	for _, want := range []string{`"startColumn": 2`, `"endColumn": 3`, `"text": "\tx := 1"`} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("WriteSARIF() = %v, want it to contain %v", w.String(), want)
+		}
+	}
+}
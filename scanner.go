@@ -0,0 +1,79 @@
+package annot
+
+import (
+	"strings"
+	"text/scanner"
+
+	"github.com/rivo/uniseg"
+)
+
+// Token is a token scanned by a Scanner.
+type Token struct {
+	// Tok is the token as returned by text/scanner, e.g. scanner.Ident.
+	Tok rune
+
+	// Text is the text of the token in the source.
+	Text string
+
+	// Pos is the position of the first character of the token.
+	Pos scanner.Position
+}
+
+// Scanner is a text/scanner.Scanner that records the positions of all
+// scanned tokens, so that annotations can be created for them.
+type Scanner struct {
+	scanner.Scanner
+
+	lines  []string
+	tokens []Token
+}
+
+// NewScanner returns a Scanner initialized to scan src.
+func NewScanner(src string) *Scanner {
+	s := &Scanner{lines: strings.Split(src, "\n")}
+	s.Init(strings.NewReader(src))
+	return s
+}
+
+// Scan scans and records the next token. See text/scanner.Scanner.Scan.
+func (s *Scanner) Scan() rune {
+	tok := s.Scanner.Scan()
+	if tok != scanner.EOF {
+		s.tokens = append(s.tokens, Token{Tok: tok, Text: s.TokenText(), Pos: s.Position})
+	}
+	return tok
+}
+
+// Tokens returns all scanned tokens.
+func (s *Scanner) Tokens() []Token {
+	return s.tokens
+}
+
+// Line returns the source line of tok with expanded tabs.
+func (s *Scanner) Line(tok Token) string {
+	return expandTabs(s.line(tok))
+}
+
+// line returns the source line of tok with its tabs.
+func (s *Scanner) line(tok Token) string {
+	if tok.Pos.Line < 1 || len(s.lines) < tok.Pos.Line {
+		return ""
+	}
+	return strings.TrimSuffix(s.lines[tok.Pos.Line-1], "\r")
+}
+
+// AnnotForToken returns an annotation with lines for the display columns
+// of tok in the line returned by Line. A token spanning multiple lines is
+// annotated up to the end of its first line.
+func (s *Scanner) AnnotForToken(tok Token, lines ...string) *Annot {
+	runes := []rune(s.line(tok))
+	prefix := string(runes[:min(max(tok.Pos.Column-1, 0), len(runes))])
+
+	text, _, _ := strings.Cut(tok.Text, "\n")
+
+	a := &Annot{Col: uniseg.StringWidth(expandTabs(prefix)), Lines: lines}
+	if width := uniseg.StringWidth(expandTabs(prefix+text)) - a.Col; width > 1 {
+		a.ColEnd = a.Col + width - 1
+	}
+	return a
+}
@@ -0,0 +1,85 @@
+package annot
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"text/scanner"
+)
+
+func TestScanner(t *testing.T) {
+	s := NewScanner("x := 1\n漢字 = \"str\"")
+	for s.Scan() != scanner.EOF {
+	}
+
+	tokens := s.Tokens()
+	if len(tokens) != 7 {
+		t.Fatalf("Tokens() len = %v, want 7", len(tokens))
+	}
+
+	tests := []struct {
+		name     string
+		tok      Token
+		wantLine string
+		want     *Annot
+	}{
+		{
+			name:     "identifier",
+			tok:      tokens[0],
+			wantLine: "x := 1",
+			want:     &Annot{Col: 0, Lines: []string{"ident"}},
+		},
+		{
+			name:     "wide identifier",
+			tok:      tokens[4],
+			wantLine: "漢字 = \"str\"",
+			want:     &Annot{Col: 0, ColEnd: 3, Lines: []string{"ident"}},
+		},
+		{
+			name:     "string after wide characters",
+			tok:      tokens[6],
+			wantLine: "漢字 = \"str\"",
+			want:     &Annot{Col: 7, ColEnd: 11, Lines: []string{"ident"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Line(tt.tok); got != tt.wantLine {
+				t.Errorf("Line() = %v, want %v", got, tt.wantLine)
+			}
+			if got := s.AnnotForToken(tt.tok, "ident"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnnotForToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_tabs(t *testing.T) {
+	s := NewScanner("if x {\n\ty := \"a\tb\"\n}")
+	for s.Scan() != scanner.EOF {
+	}
+	tokens := s.Tokens()
+
+	ident := s.AnnotForToken(tokens[3], "ident")
+	str := s.AnnotForToken(tokens[6], "string")
+	if want := (&Annot{Col: 8, Lines: []string{"ident"}}); !reflect.DeepEqual(ident, want) {
+		t.Errorf("AnnotForToken() = %v, want %v", ident, want)
+	}
+	if want := (&Annot{Col: 13, ColEnd: 17, Lines: []string{"string"}}); !reflect.DeepEqual(str, want) {
+		t.Errorf("AnnotForToken() = %v, want %v", str, want)
+	}
+
+	w := &bytes.Buffer{}
+	if err := WriteLine(w, s.Line(tokens[3]), ident, str); err != nil {
+		t.Fatalf("WriteLine() unexpected error = %v", err)
+	}
+	want := `
+        y := "a b"
+        ↑    └─┬─┘
+        │      └─ string
+        └─ ident
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("WriteLine() gotW = %v, want %v", gotW, want)
+	}
+}
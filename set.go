@@ -0,0 +1,104 @@
+package annot
+
+import (
+	"cmp"
+	"io"
+	"slices"
+)
+
+// Set is a set of annotations that are validated when they are added, so
+// that invalid annotations are reported where they are created instead of
+// when they are rendered. A Set is not safe for concurrent use.
+type Set struct {
+	r      *Renderer
+	annots []*Annot
+}
+
+// NewSet returns an empty Set rendered by a Renderer without options.
+func NewSet() *Set {
+	return NewRenderer().NewSet()
+}
+
+// NewSet returns an empty Set rendered by the Renderer.
+func (r *Renderer) NewSet() *Set {
+	return &Set{r: r}
+}
+
+// Add adds the annotations to the set. It returns an error for the first
+// annotation that is invalid or has the same column as or overlaps with an
+// annotation of the set. Annotations before the invalid one are added.
+// The positions in errors are the positions of the annotations in the set
+// ordered by Col and ColEnd.
+func (s *Set) Add(annots ...*Annot) error {
+	for _, a := range annots {
+		if err := s.add(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add validates a against its neighbors in the ordered annotations of the
+// set and inserts a.
+func (s *Set) add(a *Annot) error {
+	if err := checkFields(len(s.annots)+1, a); err != nil {
+		return err
+	}
+
+	// Insert after annotations with the same Col and ColEnd like the
+	// stable sort of arrange.
+	i, _ := slices.BinarySearchFunc(s.annots, a, func(e, a *Annot) int {
+		if c := cmp.Compare(e.Col, a.Col); c != 0 {
+			return c
+		}
+		if e.ColEnd <= a.ColEnd {
+			return -1
+		}
+		return 1
+	})
+	var prev, next *Annot
+	if i > 0 {
+		prev = s.annots[i-1]
+	}
+	if i < len(s.annots) {
+		next = s.annots[i]
+	}
+
+	switch {
+	case a.Col < s.r.origin:
+		return newColOutOfRangeError(i+1, a.Col, s.r.origin)
+	case prev != nil && s.r.duplicates(prev, a):
+		return newDuplicateColError(i, prev.Col, i+1, a.Col)
+	case next != nil && s.r.duplicates(a, next):
+		return newDuplicateColError(i+1, a.Col, i+2, next.Col)
+	case a.ColEnd != 0 && a.Col >= a.ColEnd:
+		return newColExceedsColEndError(i+1, a.Col, a.ColEnd)
+	case prev != nil && prev.ColEnd != 0 && prev.ColEnd >= a.Col:
+		return newOverlapError(i, prev, a)
+	case next != nil && a.ColEnd != 0 && a.ColEnd >= next.Col:
+		return newOverlapError(i+1, a, next)
+	}
+
+	s.annots = slices.Insert(s.annots, i, a)
+	return nil
+}
+
+// Len returns the number of annotations in the set.
+func (s *Set) Len() int {
+	return len(s.annots)
+}
+
+// Annots returns the annotations of the set ordered by Col and ColEnd.
+func (s *Set) Annots() []*Annot {
+	return slices.Clone(s.annots)
+}
+
+// Write renders the annotations of the set and writes them to a writer w.
+func (s *Set) Write(w io.Writer) error {
+	return s.r.Write(w, s.annots...)
+}
+
+// String returns the rendered annotations of the set as a string.
+func (s *Set) String() string {
+	return s.r.String(s.annots...)
+}
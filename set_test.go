@@ -0,0 +1,108 @@
+package annot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSet_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		annots  []*Annot
+		wantErr error
+	}{
+		{
+			name: "valid in any order",
+			annots: []*Annot{
+				{Col: 8, Lines: []string{"arrow"}},
+				{Col: 0, ColEnd: 3, Lines: []string{"range"}},
+				{Col: 5, Lines: []string{"between"}},
+			},
+		},
+		{
+			name: "same column",
+			annots: []*Annot{
+				{Col: 3, Lines: []string{"first"}},
+				{Col: 3, Lines: []string{"second"}},
+			},
+			wantErr: &DuplicateColError{},
+		},
+		{
+			name: "within duplicate window before annotation",
+			opts: []Option{WithDuplicateWindow(1)},
+			annots: []*Annot{
+				{Col: 3, Lines: []string{"first"}},
+				{Col: 2, Lines: []string{"second"}},
+			},
+			wantErr: &DuplicateColError{},
+		},
+		{
+			name: "overlaps previous range",
+			annots: []*Annot{
+				{Col: 0, ColEnd: 3, Lines: []string{"range"}},
+				{Col: 3, Lines: []string{"arrow"}},
+			},
+			wantErr: &OverlapError{},
+		},
+		{
+			name: "overlaps next annotation",
+			annots: []*Annot{
+				{Col: 3, Lines: []string{"arrow"}},
+				{Col: 0, ColEnd: 3, Lines: []string{"range"}},
+			},
+			wantErr: &OverlapError{},
+		},
+		{
+			name: "col equals col end",
+			annots: []*Annot{
+				{Col: 3, ColEnd: 3},
+			},
+			wantErr: &ColExceedsColEndError{},
+		},
+		{
+			name:    "column lower than origin",
+			opts:    []Option{WithOrigin(1)},
+			annots:  []*Annot{{Col: 0}},
+			wantErr: &ColOutOfRangeError{},
+		},
+		{
+			name:    "nil annotation",
+			annots:  []*Annot{nil},
+			wantErr: &NilAnnotError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRenderer(tt.opts...).NewSet()
+			err := s.Add(tt.annots...)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if s.Len() != len(tt.annots)-1 {
+					t.Errorf("Len() = %v, want %v", s.Len(), len(tt.annots)-1)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Add() unexpected error = %v", err)
+			}
+			if got, want := s.String(), String(tt.annots...); got != want {
+				t.Errorf("String() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSet_Annots(t *testing.T) {
+	s := NewSet()
+	second := &Annot{Col: 4, Lines: []string{"second"}}
+	first := &Annot{Col: 0, ColEnd: 2, Lines: []string{"first"}}
+	if err := s.Add(second, first); err != nil {
+		t.Fatalf("Add() unexpected error = %v", err)
+	}
+	if got := s.Annots(); len(got) != 2 || got[0] != first || got[1] != second {
+		t.Errorf("Annots() = %v, want ordered by Col", got)
+	}
+}
@@ -0,0 +1,70 @@
+package annot
+
+import "maps"
+
+// DefaultSeverityStyles returns the styles of WithSeverityStyles for
+// common terminals: red errors, yellow warnings, blue infos and gray
+// hints.
+func DefaultSeverityStyles() map[Severity]Style {
+	return map[Severity]Style{
+		SeverityError:   Red,
+		SeverityWarning: Yellow,
+		SeverityInfo:    Blue,
+		SeverityHint:    Gray,
+	}
+}
+
+// WithSeverityStyles styles all parts of annotations by their Severity,
+// e.g. with DefaultSeverityStyles. The Style of an annotation takes
+// precedence over the style of its severity, which takes precedence over
+// the styles of WithStyles.
+func WithSeverityStyles(styles map[Severity]Style) Option {
+	return func(r *Renderer) {
+		r.severityStyles = maps.Clone(styles)
+	}
+}
+
+// WithSeverityArrowheads draws the arrowheads of annotations by their
+// Severity, e.g. "▲" for errors and "↑" for other severities, so
+// severities are distinguished without colors. Every arrowhead needs to
+// have a display width of 1. Severities without an arrowhead are drawn
+// with the arrowhead of the theme.
+func WithSeverityArrowheads(arrowheads map[Severity]string) Option {
+	return func(r *Renderer) {
+		r.severityArrowheads = maps.Clone(arrowheads)
+	}
+}
+
+// WithSeverityStacking stacks more important annotations higher. Of
+// annotations with the same Col the one with the highest Severity is
+// rendered. The lines of an annotation are not pushed to a lower row if
+// they fit with the spacing of WithGap(1) and its Severity is higher than
+// the severities of the annotations to its right that its lines can
+// reach. Annotations further right, after a stem that no line of the
+// preceding annotations reaches, are laid out independently and are not
+// compared.
+func WithSeverityStacking() Option {
+	return func(r *Renderer) {
+		r.severityStacking = true
+	}
+}
+
+// arrowhead returns the arrowhead of a.
+func (r *Renderer) arrowhead(a *Annot) string {
+	if arrowhead := r.severityArrowheads[a.Severity]; arrowhead != "" {
+		return arrowhead
+	}
+	return r.glyphs.Arrowhead
+}
+
+// outranks reports whether the severity of a is higher than the
+// severities of all annotations of others, the annotations to the right of
+// a in its cluster.
+func outranks(a *Annot, others []*Annot) bool {
+	for _, o := range others {
+		if o.Severity >= a.Severity {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package annot
+
+import "testing"
+
+func TestWithSeverityStyles(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Severity: SeverityError, Lines: []string{"e"}},
+		{Col: 2, Severity: SeverityWarning, Style: Bold, Lines: []string{"w"}},
+		{Col: 4, Lines: []string{"n"}},
+	}
+	got := NewRenderer(WithForceColor(), WithSeverityStyles(DefaultSeverityStyles()), WithStyles(Styles{Text: Italic})).String(annots...)
+	want := "" +
+		"\x1b[31m↑\x1b[0m \x1b[1m↑\x1b[0m ↑\n" +
+		"\x1b[31m│\x1b[0m \x1b[1m│\x1b[0m └─ \x1b[3mn\x1b[0m\n" +
+		"\x1b[31m│\x1b[0m \x1b[1m└─ \x1b[0m\x1b[1mw\x1b[0m\n" +
+		"\x1b[31m└─ \x1b[0m\x1b[31me\x1b[0m\n"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestWithSeverityArrowheads(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Severity: SeverityError, Lines: []string{"e"}},
+		{Col: 2, Severity: SeverityHint, Lines: []string{"h"}},
+		{Col: 4, Lines: []string{"n"}},
+		{Col: 6, ColEnd: 8, Severity: SeverityError, Lines: []string{"r"}},
+	}
+	got := NewRenderer(WithSeverityArrowheads(map[Severity]string{SeverityError: "▲", SeverityHint: "△"})).String(annots...)
+	want := `
+▲ △ ↑ └┬┘
+│ │ │  └─ r
+│ │ └─ n
+│ └─ h
+└─ e
+`
+	if got = "\n" + got; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestWithSeverityStacking(t *testing.T) {
+	tests := []struct {
+		name   string
+		annots []*Annot
+		want   string
+	}{
+		{
+			name: "higher severity is not pushed down",
+			annots: []*Annot{
+				{Col: 0, Severity: SeverityError, Lines: []string{"ab"}},
+				{Col: 6, Lines: []string{"c"}},
+			},
+			want: `
+↑     ↑
+└─ ab └─ c
+`,
+		},
+		{
+			name: "lower severity is pushed down",
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"ab"}},
+				{Col: 6, Severity: SeverityError, Lines: []string{"c"}},
+			},
+			want: `
+↑     ↑
+│     └─ c
+└─ ab
+`,
+		},
+		{
+			name: "highest severity of same column is rendered",
+			annots: []*Annot{
+				{Col: 1, Severity: SeverityInfo, Lines: []string{"info"}},
+				{Col: 1, ColEnd: 3, Severity: SeverityError, Lines: []string{"error"}},
+				{Col: 1, Severity: SeverityWarning, Lines: []string{"warning"}},
+			},
+			want: `
+ └┬┘
+  └─ error
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := "\n" + NewRenderer(WithSeverityStacking()).String(tt.annots...); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
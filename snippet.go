@@ -0,0 +1,32 @@
+package annot
+
+// Rebase returns copies of the annotations of a snippet of a line, e.g.
+// of line[start:end] printed instead of the whole line, with columns
+// relative to start. Col and ColEnd of annots are columns of the line.
+// Ranges partly inside the snippet are cut at its edges. Annotations
+// outside the snippet are not copied and returned as clipped in the order
+// they were passed in. An end of 0 or less is the end of the line.
+func Rebase(start, end int, annots ...*Annot) (rebased, clipped []*Annot) {
+	for _, a := range annots {
+		if a == nil {
+			rebased = append(rebased, a)
+			continue
+		}
+		col, colEnd := a.Col, max(a.Col, a.ColEnd)
+		if colEnd < start || end > 0 && col >= end {
+			clipped = append(clipped, a)
+			continue
+		}
+		col = max(col, start)
+		if end > 0 {
+			colEnd = min(colEnd, end-1)
+		}
+		c := *a
+		c.Col, c.ColEnd = col-start, 0
+		if colEnd > col {
+			c.ColEnd = colEnd - start
+		}
+		rebased = append(rebased, &c)
+	}
+	return rebased, clipped
+}
@@ -0,0 +1,76 @@
+package annot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRebase(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, end  int
+		annots      []*Annot
+		wantRebased []*Annot
+		wantClipped []*Annot
+	}{
+		{
+			name:        "inside",
+			start:       4,
+			end:         10,
+			annots:      []*Annot{{Col: 4}, {Col: 6, ColEnd: 9}},
+			wantRebased: []*Annot{{Col: 0}, {Col: 2, ColEnd: 5}},
+		},
+		{
+			name:        "outside",
+			start:       4,
+			end:         10,
+			annots:      []*Annot{{Col: 3}, {Col: 5}, {Col: 10}, {Col: 0, ColEnd: 3}},
+			wantRebased: []*Annot{{Col: 1}},
+			wantClipped: []*Annot{{Col: 3}, {Col: 10}, {Col: 0, ColEnd: 3}},
+		},
+		{
+			name:        "range cut at edges",
+			start:       4,
+			end:         10,
+			annots:      []*Annot{{Col: 2, ColEnd: 6}, {Col: 8, ColEnd: 12}},
+			wantRebased: []*Annot{{Col: 0, ColEnd: 2}, {Col: 4, ColEnd: 5}},
+		},
+		{
+			name:        "range cut to single column",
+			start:       4,
+			end:         10,
+			annots:      []*Annot{{Col: 0, ColEnd: 4}},
+			wantRebased: []*Annot{{Col: 0}},
+		},
+		{
+			name:        "end of line",
+			start:       4,
+			annots:      []*Annot{{Col: 20, ColEnd: 30}},
+			wantRebased: []*Annot{{Col: 16, ColEnd: 26}},
+		},
+		{
+			name:        "nil annotation",
+			annots:      []*Annot{nil},
+			wantRebased: []*Annot{nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rebased, clipped := Rebase(tt.start, tt.end, tt.annots...)
+			if !reflect.DeepEqual(rebased, tt.wantRebased) {
+				t.Errorf("Rebase() rebased = %v, want %v", rebased, tt.wantRebased)
+			}
+			if !reflect.DeepEqual(clipped, tt.wantClipped) {
+				t.Errorf("Rebase() clipped = %v, want %v", clipped, tt.wantClipped)
+			}
+		})
+	}
+}
+
+func TestRebase_copies(t *testing.T) {
+	a := &Annot{Col: 5, Lines: []string{"x"}}
+	rebased, _ := Rebase(5, 0, a)
+	if a.Col != 5 || rebased[0] == a {
+		t.Errorf("Rebase() changed the annotation, want a copy")
+	}
+}
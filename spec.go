@@ -0,0 +1,64 @@
+package annot
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// annotJSON is Annot without methods, so that encoding/json does not call
+// MarshalJSON and UnmarshalJSON recursively.
+type annotJSON Annot
+
+// MarshalJSON returns the exported fields of a as JSON object, e.g.
+// {"col":4,"colEnd":11,"lines":["adjective"],"severity":"warning"}.
+// Fields with zero values except Col are omitted.
+func (a *Annot) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*annotJSON)(a))
+}
+
+// UnmarshalJSON sets the fields of a to the fields of a JSON object of
+// MarshalJSON. Fields that are not set are zero. It returns an error for
+// unknown fields, a *FieldRangeError for fields that cannot be rendered
+// and a *StyleError for an invalid Style.
+func (a *Annot) UnmarshalJSON(data []byte) error {
+	return a.unmarshalJSON(1, data)
+}
+
+// unmarshalJSON is UnmarshalJSON with errors of the annotation at
+// annotPos.
+func (a *Annot) unmarshalJSON(annotPos int, data []byte) error {
+	var aj annotJSON
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&aj); err != nil {
+		return err
+	}
+	if err := checkFields(annotPos, (*Annot)(&aj)); err != nil {
+		return err
+	}
+	*a = Annot(aj)
+	return nil
+}
+
+// LoadSpec reads a JSON array of annotations of MarshalJSON from r, e.g.
+// an annotation set of a config file. Errors of an annotation have its
+// position in the array. It returns a *NilAnnotError for null elements.
+func LoadSpec(r io.Reader) ([]*Annot, error) {
+	var elems []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elems); err != nil {
+		return nil, err
+	}
+	var annots []*Annot
+	for eIdx, elem := range elems {
+		if string(elem) == "null" {
+			return nil, newNilAnnotError(eIdx + 1)
+		}
+		a := &Annot{}
+		if err := a.unmarshalJSON(eIdx+1, elem); err != nil {
+			return nil, err
+		}
+		annots = append(annots, a)
+	}
+	return annots, nil
+}
@@ -0,0 +1,107 @@
+package annot
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnnot_MarshalJSON(t *testing.T) {
+	a := &Annot{Col: 4, ColEnd: 11, Lines: []string{"adjective"}, Severity: SeverityWarning, Style: Red}
+	// Rendering sets unexported fields, which must not be marshaled.
+	_ = String(a)
+	got, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error = %v", err)
+	}
+	want := `{"col":4,"colEnd":11,"lines":["adjective"],"severity":"warning","style":"31"}`
+	if string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestLoadSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []*Annot
+		wantErr error
+	}{
+		{
+			name: "annotations",
+			spec: `[
+				{"col": 0, "lines": ["article"], "severity": "note"},
+				{"col": 4, "colEnd": 11, "lines": ["adjective"], "severity": "error", "secondary": true, "seeLine": 2}
+			]`,
+			want: []*Annot{
+				{Col: 0, Lines: []string{"article"}},
+				{Col: 4, ColEnd: 11, Lines: []string{"adjective"}, Severity: SeverityError, Secondary: true, SeeLine: 2},
+			},
+		},
+		{
+			name:    "unknown severity",
+			spec:    `[{"col": 0, "severity": "fatal"}]`,
+			wantErr: &UnknownSeverityError{},
+		},
+		{
+			name:    "column higher than MaxCol",
+			spec:    `[{"col": 1000000000}]`,
+			wantErr: &FieldRangeError{},
+		},
+		{
+			name:    "escape sequence in style",
+			spec:    `[{"col": 0, "style": "0m\u001b]0;title\u0007"}]`,
+			wantErr: &StyleError{},
+		},
+		{
+			name:    "null annotation",
+			spec:    `[{"col": 0}, null]`,
+			wantErr: &NilAnnotError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadSpec(strings.NewReader(tt.spec))
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("LoadSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadSpec() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSpec_position(t *testing.T) {
+	_, err := LoadSpec(strings.NewReader(`[{"col": 0}, {"col": 0}, {"col": 2000000}]`))
+	want := "annot: in 3. annotation Col 2000000 needs to be between 0 and 1048576"
+	if err == nil || err.Error() != want {
+		t.Errorf("LoadSpec() error = %v, want %v", err, want)
+	}
+}
+
+func TestLoadSpec_unknownField(t *testing.T) {
+	if _, err := LoadSpec(strings.NewReader(`[{"col": 0, "colour": "red"}]`)); err == nil {
+		t.Errorf("LoadSpec() error = nil, want error for unknown field")
+	}
+}
+
+func TestLoadSpec_roundTrip(t *testing.T) {
+	annots := []*Annot{
+		{Col: 1, Lines: []string{"a", "b"}, MaxWidth: 10, Bullet: "• ", HangingIndent: 2, Paragraphs: true},
+		{Col: 4, ColEnd: 8, Severity: SeverityHint, Style: Bold.Combine(Blue), Line: 3},
+	}
+	data, err := json.Marshal(annots)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error = %v", err)
+	}
+	got, err := LoadSpec(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("LoadSpec() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, annots) {
+		t.Errorf("LoadSpec() = %v, want %v", got, annots)
+	}
+}
@@ -0,0 +1,187 @@
+package annot
+
+import (
+	"io"
+	"os"
+)
+
+// Style is a list of ANSI SGR parameters separated by ";", e.g. "1;31"
+// for bold red text. An empty Style does not style.
+type Style string
+
+// Common styles. Styles are combined with Combine.
+const (
+	Bold      Style = "1"
+	Dim       Style = "2"
+	Italic    Style = "3"
+	Underline Style = "4"
+	Red       Style = "31"
+	Green     Style = "32"
+	Yellow    Style = "33"
+	Blue      Style = "34"
+	Magenta   Style = "35"
+	Cyan      Style = "36"
+	Gray      Style = "90"
+)
+
+// Combine returns a style of s and others, e.g. Bold.Combine(Red).
+func (s Style) Combine(others ...Style) Style {
+	for _, o := range others {
+		switch {
+		case o == "":
+		case s == "":
+			s = o
+		default:
+			s += ";" + o
+		}
+	}
+	return s
+}
+
+// valid reports whether s has only digits and ";", so it cannot inject
+// other escape sequences.
+func (s Style) valid() bool {
+	for _, c := range []byte(s) {
+		if (c < '0' || '9' < c) && c != ';' {
+			return false
+		}
+	}
+	return true
+}
+
+// Styles are the styles of the parts of rendered annotations.
+type Styles struct {
+	// Marker is the style of arrowheads and ranges in the first row.
+	Marker Style
+
+	// Stem is the style of the pipes of stems.
+	Stem Style
+
+	// Connector is the style of the connectors between stems and lines.
+	Connector Style
+
+	// Text is the style of the lines of annotations.
+	Text Style
+}
+
+// WithStyles styles the parts of rendered annotations with ANSI escape
+// sequences, e.g. dim stems and bold text. Styles do not change the
+// layout and are ignored by WithCanonical. Styles are only written if the
+// writer is an *os.File that is a terminal and the environment variable
+// NO_COLOR is not set, unless WithForceColor is set. Buffers and other
+// writers get no styles by default.
+func WithStyles(styles Styles) Option {
+	return func(r *Renderer) {
+		r.styles = styles
+	}
+}
+
+// colorMode is whether styles are written.
+type colorMode int
+
+const (
+	colorAuto colorMode = iota
+	colorForce
+	colorNever
+)
+
+// WithForceColor writes styles regardless of NO_COLOR and of whether the
+// writer is a terminal, e.g. for a pager that interprets ANSI escape
+// sequences or for rendering into a buffer.
+func WithForceColor() Option {
+	return func(r *Renderer) {
+		r.color = colorForce
+	}
+}
+
+// WithoutColor never writes styles, including the Style of annotations.
+func WithoutColor() Option {
+	return func(r *Renderer) {
+		r.color = colorNever
+	}
+}
+
+// colored reports whether styles are written to w. Only terminals get
+// styles by default. A nil w is a buffer of the caller.
+func (r *Renderer) colored(w io.Writer) bool {
+	switch r.color {
+	case colorForce:
+		return true
+	case colorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// coloredFor returns a Renderer that writes styles to internal buffers if
+// r writes styles to w, so output that is buffered before it is written
+// to w is styled like output written to w directly.
+func (r *Renderer) coloredFor(w io.Writer) *Renderer {
+	if r.color != colorAuto {
+		return r
+	}
+	c := *r
+	c.color = colorNever
+	if r.colored(w) {
+		c.color = colorForce
+	}
+	return &c
+}
+
+// isTerminal reports whether f is a terminal. Character devices are
+// treated as terminals.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// of returns the style of segments of the kind k.
+func (s *Styles) of(k SegmentKind) Style {
+	switch k {
+	case ArrowSegment, RangeSegment:
+		return s.Marker
+	case PipeSegment:
+		return s.Stem
+	case ConnectorSegment:
+		return s.Connector
+	case TextSegment, RefSegment:
+		return s.Text
+	default:
+		return ""
+	}
+}
+
+// style returns the style of the segment s. The intensity of the
+// emphasis of the annotation is added to the style of text.
+func (r *Renderer) style(s Segment) Style {
+	style := r.styles.of(s.Kind)
+	if s.Annot == nil {
+		return style
+	}
+	if severityStyle := r.severityStyles[s.Annot.Severity]; severityStyle != "" {
+		style = severityStyle
+	}
+	if s.Annot.Style != "" {
+		style = s.Annot.Style
+	}
+	if s.Kind == TextSegment || s.Kind == RefSegment {
+		style = style.Combine(s.Annot.Emphasis.intensity())
+	}
+	return style
+}
+
+// appendStyled appends text styled with style to dst.
+func appendStyled(dst []byte, style Style, text string) []byte {
+	if style == "" || text == "" {
+		return append(dst, text...)
+	}
+	dst = append(dst, "\x1b["...)
+	dst = append(dst, style...)
+	dst = append(dst, 'm')
+	dst = append(dst, text...)
+	return append(dst, "\x1b[0m"...)
+}
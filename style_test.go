@@ -0,0 +1,138 @@
+package annot
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithStyles(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, ColEnd: 2, Lines: []string{"range"}},
+		{Col: 4, Lines: []string{"arrow"}},
+	}
+	styles := Styles{Marker: Bold, Stem: Dim, Connector: Gray, Text: Bold.Combine(Red)}
+
+	got := NewRenderer(WithForceColor(), WithStyles(styles)).String(annots...)
+	want := "" +
+		"\x1b[1m└┬┘\x1b[0m \x1b[1m↑\x1b[0m\n" +
+		" \x1b[2m│\x1b[0m  \x1b[90m└─ \x1b[0m\x1b[1;31marrow\x1b[0m\n" +
+		" \x1b[2m│\x1b[0m\n" +
+		" \x1b[90m└─ \x1b[0m\x1b[1;31mrange\x1b[0m\n"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if got := NewRenderer(WithStyles(styles), WithCanonical()).String(annots...); strings.Contains(got, "\x1b") {
+		t.Errorf("String() with WithCanonical() = %q, want no escape sequences", got)
+	}
+}
+
+func TestAnnot_Style(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, Style: Red, Lines: []string{"error"}},
+		{Col: 2, Lines: []string{"plain"}},
+	}
+	got := NewRenderer(WithForceColor(), WithStyles(Styles{Text: Bold})).String(annots...)
+	want := "" +
+		"\x1b[31m↑\x1b[0m ↑\n" +
+		"\x1b[31m│\x1b[0m └─ \x1b[1mplain\x1b[0m\n" +
+		"\x1b[31m│\x1b[0m\n" +
+		"\x1b[31m└─ \x1b[0m\x1b[31merror\x1b[0m\n"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if plain := NewRenderer(WithCanonical()).String(annots...); plain != "↑ ↑\n│ └─ plain\n│\n└─ error\n" {
+		t.Errorf("String() in canonical mode = %q, want the layout without styles", plain)
+	}
+}
+
+func TestWithStyles_color(t *testing.T) {
+	a := &Annot{Col: 0, Lines: []string{"x"}}
+	const styled, plain = "\x1b[1m↑\x1b[0m\n└─ x\n", "↑\n└─ x\n"
+	tests := []struct {
+		name    string
+		noColor string
+		file    bool
+		opts    []Option
+		want    string
+	}{
+		{name: "buffer", want: plain},
+		{name: "force color in buffer", opts: []Option{WithForceColor()}, want: styled},
+		{name: "file is not a terminal", file: true, want: plain},
+		{name: "NO_COLOR", noColor: "1", want: plain},
+		{name: "force color", noColor: "1", file: true, opts: []Option{WithForceColor()}, want: styled},
+		{name: "without color", opts: []Option{WithoutColor()}, want: plain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			r := NewRenderer(append([]Option{WithStyles(Styles{Marker: Bold})}, tt.opts...)...)
+			var got string
+			if tt.file {
+				f, err := os.CreateTemp(t.TempDir(), "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+				if err := r.Write(f, a); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := f.Seek(0, io.SeekStart); err != nil {
+					t.Fatal(err)
+				}
+				b, err := io.ReadAll(f)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(b)
+			} else {
+				b := &bytes.Buffer{}
+				if err := r.Write(b, a); err != nil {
+					t.Fatal(err)
+				}
+				got = b.String()
+			}
+			if got != tt.want {
+				t.Errorf("Write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyle_Combine(t *testing.T) {
+	tests := []struct {
+		s      Style
+		others []Style
+		want   Style
+	}{
+		{s: "", others: nil, want: ""},
+		{s: "", others: []Style{Red}, want: "31"},
+		{s: Bold, others: []Style{"", Red, Underline}, want: "1;31;4"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Combine(tt.others...); got != tt.want {
+			t.Errorf("Combine() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRenderer_coloredFor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	entries := []Entry{{LineNum: 1, Line: "x", Annots: []*Annot{{Col: 0, Lines: []string{"a"}}}}}
+	for _, force := range []bool{false, true} {
+		opts := []Option{WithStyles(Styles{Marker: Bold})}
+		if force {
+			opts = append(opts, WithForceColor())
+		}
+		w := &bytes.Buffer{}
+		if err := NewRenderer(opts...).WriteBatch(w, entries); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(w.String(), "\x1b["); got != force {
+			t.Errorf("WriteBatch() with force color %v styled = %v, want %v", force, got, force)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package annot
+
+import (
+	"io"
+	"slices"
+)
+
+// WriteLine writes the annotated line text followed by the rendered
+// annotations to a writer w. See [Renderer.WriteLine].
+func WriteLine(w io.Writer, text string, annots ...*Annot) error {
+	return NewRenderer().WriteLine(w, text, annots...)
+}
+
+// WriteLine writes the annotated line text followed by the rendered
+// annotations to a writer w. If Col or ColEnd of an annotation is not a
+// column of text, WriteLine returns a *ColExceedsLineError and writes
+// nothing. The end of text is its display width counted from the origin.
+//
+// An arrow or range that starts in the second column of a wide character,
+// e.g. of "世", is moved to the first column of the character and a range
+// that ends in its first column is extended to its second column. With
+// WithStrict, WriteLine returns a *WideCharError instead. The annotations
+// passed in are not changed.
+func (r *Renderer) WriteLine(w io.Writer, text string, annots ...*Annot) error {
+	cells := r.cells(text)
+	annots = slices.Clone(annots)
+	for aIdx, a := range annots {
+		if a == nil {
+			continue
+		}
+		fitted, err := r.fitLine(aIdx+1, a, cells)
+		if err != nil {
+			return err
+		}
+		annots[aIdx] = fitted
+	}
+
+	l, err := r.Layout(annots...)
+	if l == nil {
+		return err
+	}
+	if _, writeErr := io.WriteString(w, text+"\n"); writeErr != nil {
+		return writeErr
+	}
+	if writeErr := l.Write(w); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// fitLine returns the annotation a at annotPos fitted to the cells of its
+// line. It returns a *ColExceedsLineError if Col or ColEnd is not lower
+// than the end of the line. A copy of a is returned if Col or ColEnd is
+// moved out of the middle of a wide character.
+func (r *Renderer) fitLine(annotPos int, a *Annot, cells []string) (*Annot, error) {
+	end := len(cells) + r.origin
+	switch {
+	case a.Col >= end:
+		return nil, newColExceedsLineError(annotPos, "Col", a.Col, end)
+	case a.ColEnd >= end:
+		return nil, newColExceedsLineError(annotPos, "ColEnd", a.ColEnd, end)
+	}
+
+	col, colEnd := a.Col-r.origin, a.ColEnd-r.origin
+	for col > 0 && cells[col] == "" {
+		col--
+	}
+	for a.ColEnd != 0 && 0 <= colEnd && colEnd+1 < len(cells) && cells[colEnd+1] == "" {
+		colEnd++
+	}
+	switch {
+	case col+r.origin != a.Col:
+		if r.strict {
+			return nil, newWideCharError(annotPos, "Col", a.Col)
+		}
+	case a.ColEnd != 0 && colEnd+r.origin != a.ColEnd:
+		if r.strict {
+			return nil, newWideCharError(annotPos, "ColEnd", a.ColEnd)
+		}
+	default:
+		return a, nil
+	}
+	c := *a
+	c.Col = col + r.origin
+	if a.ColEnd != 0 {
+		c.ColEnd = colEnd + r.origin
+	}
+	return &c, nil
+}
@@ -0,0 +1,132 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRenderer_WriteLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		text    string
+		annots  []*Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "line and annotations",
+			text: "The quick fox",
+			annots: []*Annot{
+				{Col: 4, ColEnd: 8, Lines: []string{"adjective"}},
+				{Col: 12, Lines: []string{"x"}},
+			},
+			wantW: `
+The quick fox
+    └─┬─┘   ↑
+      │     └─ x
+      │
+      └─ adjective
+`,
+		},
+		{
+			name: "wide characters and origin",
+			opts: []Option{WithOrigin(1)},
+			text: "世界",
+			annots: []*Annot{
+				{Col: 3, ColEnd: 4, Lines: []string{"界"}},
+			},
+			wantW: `
+世界
+  ├┘
+  └─ 界
+`,
+		},
+		{
+			name: "arrow in second column of wide character",
+			text: "a世b",
+			annots: []*Annot{
+				{Col: 2, Lines: []string{"世"}},
+			},
+			wantW: `
+a世b
+ ↑
+ └─ 世
+`,
+		},
+		{
+			name: "range end in first column of wide character",
+			text: "a世b",
+			annots: []*Annot{
+				{Col: 0, ColEnd: 1, Lines: []string{"a世"}},
+			},
+			wantW: `
+a世b
+└┬┘
+ └─ a世
+`,
+		},
+		{
+			name: "strict",
+			opts: []Option{WithStrict()},
+			text: "a世b",
+			annots: []*Annot{
+				{Col: 2},
+			},
+			wantW:   "\n",
+			wantErr: &WideCharError{},
+		},
+		{
+			name: "column after end of line",
+			text: "abc",
+			annots: []*Annot{
+				{Col: 0},
+				{Col: 3},
+			},
+			wantW:   "\n",
+			wantErr: &ColExceedsLineError{},
+		},
+		{
+			name: "range end after end of line",
+			text: "abc",
+			annots: []*Annot{
+				{Col: 1, ColEnd: 3},
+			},
+			wantW:   "\n",
+			wantErr: &ColExceedsLineError{},
+		},
+		{
+			name: "invalid annotations",
+			text: "abc",
+			annots: []*Annot{
+				{Col: 0, ColEnd: 2},
+				{Col: 1},
+			},
+			wantW:   "\n",
+			wantErr: &OverlapError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := NewRenderer(tt.opts...).WriteLine(w, tt.text, tt.annots...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("WriteLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteLine() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestRenderer_WriteLine_unchanged(t *testing.T) {
+	a := &Annot{Col: 2, ColEnd: 3}
+	if err := NewRenderer().WriteLine(&bytes.Buffer{}, "a世界", a); err != nil {
+		t.Fatalf("WriteLine() unexpected error = %v", err)
+	}
+	if a.Col != 2 || a.ColEnd != 3 {
+		t.Errorf("WriteLine() changed annotation to Col %d and ColEnd %d", a.Col, a.ColEnd)
+	}
+}
@@ -0,0 +1,44 @@
+package annot
+
+import (
+	"slices"
+	"strconv"
+)
+
+// WithSummary appends a line that counts the rendered annotations by
+// Severity, e.g. "4 annotations: 1 error, 2 warnings, 1 note". Removed
+// and skipped annotations are not counted. Annotations with an unknown
+// Severity are counted as notes.
+func WithSummary() Option {
+	return func(r *Renderer) {
+		r.summary = true
+	}
+}
+
+// appendSummary appends the row of the summary if the Renderer writes a
+// summary.
+func (l *Layout) appendSummary() {
+	if !l.r.summary {
+		return
+	}
+	row := []Segment{{Text: summary(l.annots), Kind: TextSegment, Index: -1}}
+	if width := l.r.available(l.width); l.r.overflow != overflowNone && width > 0 {
+		// The summary starts at column 0, so it is only truncated.
+		row, _ = l.r.clip(row, width)
+	}
+	// Clip, so that cached rows are never overwritten.
+	l.rows = append(slices.Clip(l.rows), row)
+}
+
+// summary returns the counts of annots by severity.
+func summary(annots []*Annot) string {
+	counts := map[Severity]int{}
+	for _, a := range annots {
+		counts[a.Severity]++
+	}
+	noun := " annotations: "
+	if len(annots) == 1 {
+		noun = " annotation: "
+	}
+	return strconv.Itoa(len(annots)) + noun + joinCounts(counts)
+}
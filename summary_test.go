@@ -0,0 +1,91 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		annots []*Annot
+		wantW  string
+	}{
+		{
+			name: "severities",
+			annots: []*Annot{
+				{Col: 0, Severity: SeverityError, Lines: []string{"error"}},
+				{Col: 2, Severity: SeverityWarning, Lines: []string{"warning"}},
+				{Col: 4, Severity: SeverityWarning, Lines: []string{"warning"}},
+				{Col: 6, Lines: []string{"note"}},
+			},
+			wantW: `
+↑ ↑ ↑ ↑
+│ │ │ └─ note
+│ │ │
+│ │ └─ warning
+│ │
+│ └─ warning
+│
+└─ error
+4 annotations: 1 error, 2 warnings, 1 note
+`,
+		},
+		{
+			name: "unknown severities",
+			annots: []*Annot{
+				{Col: 0, Severity: -1, Lines: []string{"negative"}},
+				{Col: 2, Severity: SeverityError + 1, Lines: []string{"above error"}},
+				{Col: 4, Lines: []string{"note"}},
+			},
+			wantW: `
+↑ ↑ ↑
+│ │ └─ note
+│ │
+│ └─ above error
+│
+└─ negative
+3 annotations: 3 notes
+`,
+		},
+		{
+			name: "removed annotation",
+			opts: []Option{WithWidth(20)},
+			annots: []*Annot{
+				{Col: 0, Severity: SeverityInfo, Lines: []string{"info"}},
+				{Col: 0, Severity: SeverityError, Lines: []string{"same column"}},
+			},
+			wantW: `
+↑
+└─ info
+1 annotation: 1 info
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := NewRenderer(append(tt.opts, WithSummary())...).Write(w, tt.annots...); err != nil {
+				t.Fatalf("Write() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestWithSummary_reflow(t *testing.T) {
+	l, err := NewRenderer(WithSummary(), WithLayoutCache(1)).Layout(&Annot{Col: 0, Lines: []string{"a b"}})
+	if err != nil {
+		t.Fatalf("Layout() unexpected error = %v", err)
+	}
+	if err := l.Reflow(4); err != nil {
+		t.Fatalf("Reflow() unexpected error = %v", err)
+	}
+	want := "↑\n└─ a\n   b\n1 annotation: 1 note\n"
+	if got := l.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package annot
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FromStruct returns an annotation for every field of the struct v, or of
+// the struct v points to, with an annot tag. The tag sets the columns of
+// the field in the annotated line and an optional description:
+//
+//	type Header struct {
+//		Type    string `annot:"col=0,end=2,desc=message type"`
+//		Version int    `annot:"col=4"`
+//	}
+//
+// The keys are col for Col, end for ColEnd and desc, which ends the tag,
+// so the description can contain commas. The annotation has the line
+// "description: value" with the value of the field formatted like
+// fmt.Sprint or, without desc, "Name: value" with the name of the field.
+// Fields without an annot tag or with the tag "-" are skipped. It returns
+// a *StructTagError for an invalid tag or if v is not a struct.
+func FromStruct(v any) ([]*Annot, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, newStructTagError("", fmt.Sprintf("%T is not a struct", v))
+	}
+
+	var annots []*Annot
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		f := rt.Field(i)
+		tag, ok := f.Tag.Lookup("annot")
+		if !ok || tag == "-" {
+			continue
+		}
+		a, desc, err := parseTag(f.Name, tag)
+		if err != nil {
+			return nil, err
+		}
+		if desc == "" {
+			desc = f.Name
+		}
+		a.Lines = []string{desc + ": " + fmt.Sprint(rv.Field(i))}
+		annots = append(annots, a)
+	}
+	return annots, nil
+}
+
+// parseTag returns the annotation of the columns of an annot tag of the
+// field name and the description of the tag.
+func parseTag(name, tag string) (*Annot, string, error) {
+	a := &Annot{}
+	hasCol := false
+	desc := ""
+	for tag != "" {
+		var kv string
+		if strings.HasPrefix(tag, "desc=") {
+			kv, tag = tag, ""
+		} else {
+			kv, tag, _ = strings.Cut(tag, ",")
+		}
+		key, value, _ := strings.Cut(kv, "=")
+		switch key {
+		case "col", "end":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, "", newStructTagError(name, fmt.Sprintf("%s %q is not a number", key, value))
+			}
+			if key == "col" {
+				a.Col, hasCol = n, true
+			} else {
+				a.ColEnd = n
+			}
+		case "desc":
+			desc = value
+		default:
+			return nil, "", newStructTagError(name, fmt.Sprintf("unknown key %q", key))
+		}
+	}
+	if !hasCol {
+		return nil, "", newStructTagError(name, "col is missing")
+	}
+	return a, desc, nil
+}
@@ -0,0 +1,82 @@
+package annot
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFromStruct(t *testing.T) {
+	type header struct {
+		Type     string `annot:"col=0,end=2,desc=message type, e.g. ADT"`
+		Version  int    `annot:"col=4"`
+		Internal string `annot:"-"`
+		Comment  string
+		trigger  string `annot:"col=6,end=8,desc=event"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		want    []*Annot
+		wantErr error
+	}{
+		{
+			name: "struct",
+			v:    header{Type: "ADT", Version: 2, Internal: "x", trigger: "A01"},
+			want: []*Annot{
+				{Col: 0, ColEnd: 2, Lines: []string{"message type, e.g. ADT: ADT"}},
+				{Col: 4, Lines: []string{"Version: 2"}},
+				{Col: 6, ColEnd: 8, Lines: []string{"event: A01"}},
+			},
+		},
+		{
+			name: "pointer to struct",
+			v: &struct {
+				N int `annot:"col=1,desc="`
+			}{N: 7},
+			want: []*Annot{{Col: 1, Lines: []string{"N: 7"}}},
+		},
+		{
+			name:    "not a struct",
+			v:       "ADT",
+			wantErr: &StructTagError{},
+		},
+		{
+			name:    "nil pointer",
+			v:       (*header)(nil),
+			wantErr: &StructTagError{},
+		},
+		{
+			name: "missing col",
+			v: struct {
+				N int `annot:"end=2"`
+			}{},
+			wantErr: &StructTagError{},
+		},
+		{
+			name: "invalid number",
+			v: struct {
+				N int `annot:"col=x"`
+			}{},
+			wantErr: &StructTagError{},
+		},
+		{
+			name: "unknown key",
+			v: struct {
+				N int `annot:"col=1,width=2"`
+			}{},
+			wantErr: &StructTagError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromStruct(tt.v)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(tt.wantErr, err) {
+				t.Errorf("FromStruct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromStruct() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
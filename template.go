@@ -0,0 +1,58 @@
+package annot
+
+import "strings"
+
+// AnnotTemplate describes an annotation relative to a base column. Its
+// Lines can contain placeholders of the form {key} that are replaced when
+// the template is instantiated.
+type AnnotTemplate struct {
+	// Col is the position of the arrowhead relative to the base column.
+	Col int
+
+	// ColEnd is the end of the annotated range relative to the base
+	// column. If ColEnd is not set, no range is annotated.
+	ColEnd int
+
+	// Lines is the text of the annotation with placeholders.
+	Lines []string
+}
+
+// Instantiate creates an annotation shifted by base. Every placeholder
+// {key} in Lines is replaced by values[key]. Placeholders without a value
+// are left as they are.
+func (t *AnnotTemplate) Instantiate(base int, values map[string]string) *Annot {
+	return t.instantiate(base, placeholderReplacer(values))
+}
+
+func (t *AnnotTemplate) instantiate(base int, r *strings.Replacer) *Annot {
+	a := &Annot{Col: base + t.Col}
+	if t.ColEnd != 0 {
+		a.ColEnd = base + t.ColEnd
+	}
+	if t.Lines != nil {
+		a.Lines = make([]string, len(t.Lines))
+		for i, l := range t.Lines {
+			a.Lines[i] = r.Replace(l)
+		}
+	}
+	return a
+}
+
+// Instantiate creates annotations of all templates with the same base
+// column and values. See AnnotTemplate.Instantiate.
+func Instantiate(base int, values map[string]string, templates ...*AnnotTemplate) []*Annot {
+	r := placeholderReplacer(values)
+	annots := make([]*Annot, len(templates))
+	for i, t := range templates {
+		annots[i] = t.instantiate(base, r)
+	}
+	return annots
+}
+
+func placeholderReplacer(values map[string]string) *strings.Replacer {
+	oldNew := make([]string, 0, 2*len(values))
+	for k, v := range values {
+		oldNew = append(oldNew, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(oldNew...)
+}
@@ -0,0 +1,69 @@
+package annot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotTemplate_Instantiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *AnnotTemplate
+		base     int
+		values   map[string]string
+		want     *Annot
+	}{
+		{
+			name:     "shift arrow",
+			template: &AnnotTemplate{Col: 2, Lines: []string{"id"}},
+			base:     10,
+			want:     &Annot{Col: 12, Lines: []string{"id"}},
+		},
+		{
+			name:     "shift range",
+			template: &AnnotTemplate{Col: 0, ColEnd: 3, Lines: []string{"id"}},
+			base:     5,
+			want:     &Annot{Col: 5, ColEnd: 8, Lines: []string{"id"}},
+		},
+		{
+			name:     "replace placeholders",
+			template: &AnnotTemplate{Lines: []string{"{field} is {value}", "{field}"}},
+			values:   map[string]string{"field": "name", "value": "empty"},
+			want:     &Annot{Lines: []string{"name is empty", "name"}},
+		},
+		{
+			name:     "keep unknown placeholders",
+			template: &AnnotTemplate{Lines: []string{"{unknown} {field}"}},
+			values:   map[string]string{"field": "name"},
+			want:     &Annot{Lines: []string{"{unknown} name"}},
+		},
+		{
+			name:     "no lines",
+			template: &AnnotTemplate{Col: 1},
+			base:     1,
+			want:     &Annot{Col: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.template.Instantiate(tt.base, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Instantiate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	templates := []*AnnotTemplate{
+		{Col: 0, ColEnd: 3, Lines: []string{"{id}"}},
+		{Col: 5, Lines: []string{"flag"}},
+	}
+	got := Instantiate(4, map[string]string{"id": "42"}, templates...)
+	want := []*Annot{
+		{Col: 4, ColEnd: 7, Lines: []string{"42"}},
+		{Col: 9, Lines: []string{"flag"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Instantiate() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,362 @@
+package annot
+
+// Theme is a set of glyphs annotations are drawn with.
+type Theme int
+
+const (
+	// ThemeUnicode draws with box-drawing characters, e.g. "↑", "│" and
+	// "└─┬─┘". It is the default theme.
+	ThemeUnicode Theme = iota
+
+	// ThemeASCII draws only with ASCII characters, e.g. "^", "|" and
+	// "\--+--/", for log files and consoles that are not UTF-8.
+	ThemeASCII
+
+	// ThemeRounded draws like ThemeUnicode with rounded corners, e.g.
+	// "╰─ " and "╰─┬─╯".
+	ThemeRounded
+
+	// ThemeHeavy draws with heavy box-drawing characters, e.g. "┃", "┗━ "
+	// and "┗━┳━┛".
+	ThemeHeavy
+
+	// ThemeDouble draws with double box-drawing characters, e.g. "║",
+	// "╚═ " and "╚═╦═╝".
+	ThemeDouble
+)
+
+// GlyphSet is the set of glyphs annotations are drawn with. Every glyph
+// is one grapheme cluster with a display width of 1, except Connector,
+// Ellipsis, Gap, Gutter and Reference.
+type GlyphSet struct {
+	// Arrowhead points to the column of an annotation, e.g. "↑".
+	Arrowhead string
+
+	// Pipe draws stems, e.g. "│".
+	Pipe string
+
+	// DashedPipe draws stems of secondary annotations, e.g. "┆".
+	DashedPipe string
+
+	// DashedHorizontal draws ranges of secondary annotations like
+	// "└┄┬┄┘".
+	DashedHorizontal string
+
+	// HeavyHorizontal draws ranges of emphasized annotations like
+	// "└━┬━┘".
+	HeavyHorizontal string
+
+	// Connector connects a stem with the first line of an annotation,
+	// e.g. "└─ ".
+	Connector string
+
+	// RangeLeft, Horizontal, RangeMid and RangeRight draw a range like
+	// "└─┬─┘". Tee starts a range with the stem in its first column like
+	// "├─┘".
+	RangeLeft, Horizontal, RangeMid, RangeRight, Tee string
+
+	// Crossing is a stem crossing the line of a range in WriteDiagram,
+	// e.g. "┼".
+	Crossing string
+
+	// RightTee ends the line of a Ruler, e.g. "┤" of "├─┼─┤". Tee and
+	// Crossing draw the other ticks.
+	RightTee string
+
+	// Corner and Leader lead from a stem to a margin note like "└┄┄ ".
+	Corner, Leader string
+
+	// Ellipsis starts the line of collapsed lines, e.g. "…".
+	Ellipsis string
+
+	// Gap encloses the number of collapsed columns of
+	// WithGapCompression, e.g. "⋯" of "⋯+240⋯".
+	Gap string
+
+	// Gutter separates line numbers from lines, e.g. "│".
+	Gutter string
+
+	// Reference starts the reference of SeeLine, e.g. "→" of
+	// "→ see line 42".
+	Reference string
+
+	// GridLeft and GridRight turn the horizontal runs of WithGrid down
+	// into cells, e.g. "┌" and "┐". Corner and RangeRight start the runs.
+	GridLeft, GridRight string
+
+	// LeftArrowhead, VerticalRangeTop, VerticalRangeMid, VerticalTee and
+	// VerticalRangeBottom are the markers of WriteVertical, e.g. "←" and
+	// "┐├┘". Horizontal connects them with lines and Pipe draws the
+	// middle of ranges.
+	LeftArrowhead, VerticalRangeTop, VerticalRangeMid, VerticalTee, VerticalRangeBottom string
+}
+
+// The glyphs of ThemeUnicode, the default glyphs. They do not change in a
+// minor version of annot.
+const (
+	DefaultArrowhead           = "↑"
+	DefaultPipe                = "│"
+	DefaultDashedPipe          = "┆"
+	DefaultDashedHorizontal    = "┄"
+	DefaultHeavyHorizontal     = "━"
+	DefaultConnector           = "└─ "
+	DefaultRangeLeft           = "└"
+	DefaultHorizontal          = "─"
+	DefaultRangeMid            = "┬"
+	DefaultRangeRight          = "┘"
+	DefaultTee                 = "├"
+	DefaultCrossing            = "┼"
+	DefaultRightTee            = "┤"
+	DefaultCorner              = "└"
+	DefaultLeader              = "┄"
+	DefaultEllipsis            = "…"
+	DefaultGap                 = "⋯"
+	DefaultGutter              = "│"
+	DefaultReference           = "→"
+	DefaultGridLeft            = "┌"
+	DefaultGridRight           = "┐"
+	DefaultLeftArrowhead       = "←"
+	DefaultVerticalRangeTop    = "┐"
+	DefaultVerticalRangeMid    = "├"
+	DefaultVerticalTee         = "┬"
+	DefaultVerticalRangeBottom = "┘"
+)
+
+// themeGlyphs returns the glyphs of the theme t and whether t is a Theme.
+func themeGlyphs(t Theme) (GlyphSet, bool) {
+	switch t {
+	case ThemeUnicode:
+		return DefaultGlyphs(), true
+	case ThemeASCII:
+		return GlyphSet{
+			Arrowhead:           "^",
+			Pipe:                "|",
+			DashedPipe:          ":",
+			DashedHorizontal:    ".",
+			HeavyHorizontal:     "=",
+			Connector:           "`-- ",
+			RangeLeft:           "\\",
+			Horizontal:          "-",
+			RangeMid:            "+",
+			RangeRight:          "/",
+			Tee:                 "+",
+			Crossing:            "+",
+			RightTee:            "+",
+			Corner:              "`",
+			Leader:              ".",
+			Ellipsis:            "...",
+			Gap:                 "~",
+			Gutter:              "|",
+			Reference:           "->",
+			GridLeft:            "+",
+			GridRight:           "+",
+			LeftArrowhead:       "<",
+			VerticalRangeTop:    "\\",
+			VerticalRangeMid:    "+",
+			VerticalTee:         "+",
+			VerticalRangeBottom: "/",
+		}, true
+	case ThemeRounded:
+		return GlyphSet{
+			Arrowhead:           "↑",
+			Pipe:                "│",
+			DashedPipe:          "┆",
+			DashedHorizontal:    "┄",
+			HeavyHorizontal:     "━",
+			Connector:           "╰─ ",
+			RangeLeft:           "╰",
+			Horizontal:          "─",
+			RangeMid:            "┬",
+			RangeRight:          "╯",
+			Tee:                 "├",
+			Crossing:            "┼",
+			RightTee:            "┤",
+			Corner:              "╰",
+			Leader:              "┄",
+			Ellipsis:            "…",
+			Gap:                 "⋯",
+			Gutter:              "│",
+			Reference:           "→",
+			GridLeft:            "╭",
+			GridRight:           "╮",
+			LeftArrowhead:       "←",
+			VerticalRangeTop:    "╮",
+			VerticalRangeMid:    "├",
+			VerticalTee:         "┬",
+			VerticalRangeBottom: "╯",
+		}, true
+	case ThemeHeavy:
+		return GlyphSet{
+			Arrowhead:           "▲",
+			Pipe:                "┃",
+			DashedPipe:          "┇",
+			DashedHorizontal:    "┅",
+			HeavyHorizontal:     "━",
+			Connector:           "┗━ ",
+			RangeLeft:           "┗",
+			Horizontal:          "━",
+			RangeMid:            "┳",
+			RangeRight:          "┛",
+			Tee:                 "┣",
+			Crossing:            "╋",
+			RightTee:            "┫",
+			Corner:              "┗",
+			Leader:              "┅",
+			Ellipsis:            "…",
+			Gap:                 "⋯",
+			Gutter:              "┃",
+			Reference:           "→",
+			GridLeft:            "┏",
+			GridRight:           "┓",
+			LeftArrowhead:       "◀",
+			VerticalRangeTop:    "┓",
+			VerticalRangeMid:    "┣",
+			VerticalTee:         "┳",
+			VerticalRangeBottom: "┛",
+		}, true
+	case ThemeDouble:
+		return GlyphSet{
+			Arrowhead:           "↑",
+			Pipe:                "║",
+			DashedPipe:          "┆",
+			DashedHorizontal:    "┄",
+			HeavyHorizontal:     "━",
+			Connector:           "╚═ ",
+			RangeLeft:           "╚",
+			Horizontal:          "═",
+			RangeMid:            "╦",
+			RangeRight:          "╝",
+			Tee:                 "╠",
+			Crossing:            "╬",
+			RightTee:            "╣",
+			Corner:              "╚",
+			Leader:              "═",
+			Ellipsis:            "…",
+			Gap:                 "⋯",
+			Gutter:              "║",
+			Reference:           "→",
+			GridLeft:            "╔",
+			GridRight:           "╗",
+			LeftArrowhead:       "←",
+			VerticalRangeTop:    "╗",
+			VerticalRangeMid:    "╠",
+			VerticalTee:         "╦",
+			VerticalRangeBottom: "╝",
+		}, true
+	}
+	return GlyphSet{}, false
+}
+
+// pipe returns the pipe of the stem of a.
+func (r *Renderer) pipe(a *Annot) string {
+	if a.Secondary {
+		return r.glyphs.DashedPipe
+	}
+	return r.glyphs.Pipe
+}
+
+// DefaultGlyphs returns the default glyphs, e.g. to change one or two
+// glyphs with WithGlyphs.
+func DefaultGlyphs() GlyphSet {
+	return GlyphSet{
+		Arrowhead:           DefaultArrowhead,
+		Pipe:                DefaultPipe,
+		DashedPipe:          DefaultDashedPipe,
+		DashedHorizontal:    DefaultDashedHorizontal,
+		HeavyHorizontal:     DefaultHeavyHorizontal,
+		Connector:           DefaultConnector,
+		RangeLeft:           DefaultRangeLeft,
+		Horizontal:          DefaultHorizontal,
+		RangeMid:            DefaultRangeMid,
+		RangeRight:          DefaultRangeRight,
+		Tee:                 DefaultTee,
+		Crossing:            DefaultCrossing,
+		RightTee:            DefaultRightTee,
+		Corner:              DefaultCorner,
+		Leader:              DefaultLeader,
+		Ellipsis:            DefaultEllipsis,
+		Gap:                 DefaultGap,
+		Gutter:              DefaultGutter,
+		Reference:           DefaultReference,
+		GridLeft:            DefaultGridLeft,
+		GridRight:           DefaultGridRight,
+		LeftArrowhead:       DefaultLeftArrowhead,
+		VerticalRangeTop:    DefaultVerticalRangeTop,
+		VerticalRangeMid:    DefaultVerticalRangeMid,
+		VerticalTee:         DefaultVerticalTee,
+		VerticalRangeBottom: DefaultVerticalRangeBottom,
+	}
+}
+
+// horizontal returns the horizontal line of the range of a.
+func (r *Renderer) horizontal(a *Annot) string {
+	switch {
+	case a.Secondary:
+		return r.glyphs.DashedHorizontal
+	case a.Emphasis > EmphasisNormal:
+		return r.glyphs.HeavyHorizontal
+	}
+	return r.glyphs.Horizontal
+}
+
+// Glyphs returns the glyphs of the theme t. Unknown themes have the glyphs
+// of ThemeUnicode.
+func (t Theme) Glyphs() GlyphSet {
+	if g, ok := themeGlyphs(t); ok {
+		return g
+	}
+	return DefaultGlyphs()
+}
+
+// WithGlyphs draws annotations with the glyphs of g, e.g. of a theme
+// changed with Theme.Glyphs. Empty glyphs of g are the glyphs of
+// ThemeUnicode.
+func WithGlyphs(g GlyphSet) Option {
+	return func(r *Renderer) {
+		def := DefaultGlyphs()
+		for _, glyph := range []struct{ dst, def *string }{
+			{&g.Arrowhead, &def.Arrowhead},
+			{&g.Pipe, &def.Pipe},
+			{&g.DashedPipe, &def.DashedPipe},
+			{&g.DashedHorizontal, &def.DashedHorizontal},
+			{&g.HeavyHorizontal, &def.HeavyHorizontal},
+			{&g.Connector, &def.Connector},
+			{&g.RangeLeft, &def.RangeLeft},
+			{&g.Horizontal, &def.Horizontal},
+			{&g.RangeMid, &def.RangeMid},
+			{&g.RangeRight, &def.RangeRight},
+			{&g.Tee, &def.Tee},
+			{&g.Crossing, &def.Crossing},
+			{&g.RightTee, &def.RightTee},
+			{&g.Corner, &def.Corner},
+			{&g.Leader, &def.Leader},
+			{&g.Ellipsis, &def.Ellipsis},
+			{&g.Gap, &def.Gap},
+			{&g.Gutter, &def.Gutter},
+			{&g.Reference, &def.Reference},
+			{&g.GridLeft, &def.GridLeft},
+			{&g.GridRight, &def.GridRight},
+			{&g.LeftArrowhead, &def.LeftArrowhead},
+			{&g.VerticalRangeTop, &def.VerticalRangeTop},
+			{&g.VerticalRangeMid, &def.VerticalRangeMid},
+			{&g.VerticalTee, &def.VerticalTee},
+			{&g.VerticalRangeBottom, &def.VerticalRangeBottom},
+		} {
+			if *glyph.dst == "" {
+				*glyph.dst = *glyph.def
+			}
+		}
+		r.glyphs = g
+	}
+}
+
+// WithTheme draws annotations with the glyphs of theme. A connector set
+// by WithConnector takes precedence over the connector of theme. Unknown
+// themes are ignored.
+func WithTheme(theme Theme) Option {
+	return func(r *Renderer) {
+		if g, ok := themeGlyphs(theme); ok {
+			r.glyphs = g
+		}
+	}
+}
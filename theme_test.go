@@ -0,0 +1,195 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithTheme(t *testing.T) {
+	annots := []*Annot{
+		{Col: 0, ColEnd: 6, Lines: []string{"range"}},
+		{Col: 8, ColEnd: 10, Lines: []string{"short range"}},
+		{Col: 12, Lines: []string{"arrow", "line2", "line3"}},
+	}
+	tests := []struct {
+		name  string
+		opts  []Option
+		wantW string
+	}{
+		{
+			name: "ascii",
+			opts: []Option{WithTheme(ThemeASCII), WithCollapse(1)},
+			wantW: `
+\--+--/ \+/ ^
+   |     |  ` + "`" + `-- arrow
+   |     |      ... (+2 more lines)
+   |     |
+   |     ` + "`" + `-- short range
+   ` + "`" + `-- range
+`,
+		},
+		{
+			name: "ascii with connector",
+			opts: []Option{WithConnector("+- "), WithTheme(ThemeASCII)},
+			wantW: `
+\--+--/ \+/ ^
+   |     |  +- arrow
+   |     |     line2
+   |     |     line3
+   |     |
+   |     +- short range
+   +- range
+`,
+		},
+		{
+			name: "rounded",
+			opts: []Option{WithTheme(ThemeRounded)},
+			wantW: `
+╰──┬──╯ ╰┬╯ ↑
+   │     │  ╰─ arrow
+   │     │     line2
+   │     │     line3
+   │     │
+   │     ╰─ short range
+   ╰─ range
+`,
+		},
+		{
+			name: "heavy",
+			opts: []Option{WithTheme(ThemeHeavy)},
+			wantW: `
+┗━━┳━━┛ ┗┳┛ ▲
+   ┃     ┃  ┗━ arrow
+   ┃     ┃     line2
+   ┃     ┃     line3
+   ┃     ┃
+   ┃     ┗━ short range
+   ┗━ range
+`,
+		},
+		{
+			name: "double",
+			opts: []Option{WithTheme(ThemeDouble)},
+			wantW: `
+╚══╦══╝ ╚╦╝ ↑
+   ║     ║  ╚═ arrow
+   ║     ║     line2
+   ║     ║     line3
+   ║     ║
+   ║     ╚═ short range
+   ╚═ range
+`,
+		},
+		{
+			name: "unknown theme",
+			opts: []Option{WithTheme(-1)},
+			wantW: `
+└──┬──┘ └┬┘ ↑
+   │     │  └─ arrow
+   │     │     line2
+   │     │     line3
+   │     │
+   │     └─ short range
+   └─ range
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := NewRenderer(tt.opts...).Write(w, annots...); err != nil {
+				t.Fatalf("Write() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("Write() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestWithTheme_modes(t *testing.T) {
+	r := NewRenderer(WithTheme(ThemeASCII))
+	w := &bytes.Buffer{}
+	if err := r.WriteVertical(w, []string{"a", "b", "c"}, &Annot{Col: 0, ColEnd: 2, Lines: []string{"range"}}); err != nil {
+		t.Fatalf("WriteVertical() unexpected error = %v", err)
+	}
+	if err := r.WriteBatch(w, []Entry{{LineNum: 1, Line: "x", Annots: []*Annot{{Col: 0}}}}); err != nil {
+		t.Fatalf("WriteBatch() unexpected error = %v", err)
+	}
+	if err := NewRenderer(WithTheme(ThemeASCII), WithMarginNotes(6)).Write(w, &Annot{Col: 2, Lines: []string{"note"}}); err != nil {
+		t.Fatalf("Write() unexpected error = %v", err)
+	}
+	wantW := "\n" +
+		"a \\\n" +
+		"b +- range\n" +
+		"c /\n" +
+		"1 | x\n" +
+		"  | ^\n" +
+		"  | `-- \n" +
+		"  ^\n" +
+		"  `.. note\n"
+	if gotW := "\n" + w.String(); gotW != wantW {
+		t.Errorf("gotW = %v, want %v", gotW, wantW)
+	}
+	for _, r := range w.String() {
+		if r > 127 {
+			t.Errorf("gotW contains non-ASCII %q", r)
+		}
+	}
+}
+
+func TestWithGlyphs(t *testing.T) {
+	g := ThemeASCII.Glyphs()
+	g.Arrowhead = "*"
+	annots := []*Annot{
+		{Col: 0, ColEnd: 4, Lines: []string{"range"}},
+		{Col: 6, Lines: []string{"arrow"}},
+	}
+	tests := []struct {
+		name  string
+		g     GlyphSet
+		wantW string
+	}{
+		{
+			name: "changed theme",
+			g:    g,
+			wantW: `
+\-+-/ *
+  |   ` + "`" + `-- arrow
+  |
+  ` + "`" + `-- range
+`,
+		},
+		{
+			name: "empty glyphs of unicode theme",
+			g:    GlyphSet{RangeLeft: "╘", Horizontal: "═", RangeMid: "╤", RangeRight: "╛"},
+			wantW: `
+╘═╤═╛ ↑
+  │   └─ arrow
+  │
+  └─ range
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotW := "\n" + NewRenderer(WithGlyphs(tt.g)).String(annots...); gotW != tt.wantW {
+				t.Errorf("String() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestDefaultGlyphs(t *testing.T) {
+	g := DefaultGlyphs()
+	if g != ThemeUnicode.Glyphs() {
+		t.Errorf("DefaultGlyphs() = %v, want glyphs of ThemeUnicode", g)
+	}
+	g.Arrowhead = DefaultVerticalTee
+	if DefaultGlyphs().Arrowhead != DefaultArrowhead {
+		t.Errorf("DefaultGlyphs() changed by changing a returned glyph set")
+	}
+	if got := NewRenderer(WithGlyphs(g)).String(&Annot{Col: 1, Lines: []string{"x"}}); got != " ┬\n └─ x\n" {
+		t.Errorf("String() = %q, want %q", got, " ┬\n └─ x\n")
+	}
+}
@@ -0,0 +1,87 @@
+package annot
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// Interval is an interval of a timeline, e.g. a span of a trace or a
+// phase of a benchmark, with the lines of its annotation.
+type Interval struct {
+	// Start and End are the values of the interval on the ruler of the
+	// timeline.
+	Start, End float64
+
+	// Lines are the lines of the annotation of the interval.
+	Lines []string
+}
+
+// DurationInterval returns an interval from start to end for a ruler of
+// DurationRuler.
+func DurationInterval(start, end time.Duration, lines ...string) Interval {
+	return Interval{Start: float64(start), End: float64(end), Lines: lines}
+}
+
+// DurationRuler returns a ruler from 0 to end with durations as labels.
+// Its values are nanoseconds like the values of time.Duration.
+func DurationRuler(end time.Duration, width, ticks int) Ruler {
+	return Ruler{
+		Max:   float64(end),
+		Width: width,
+		Ticks: ticks,
+		Format: func(v float64) string {
+			return time.Duration(v).String()
+		},
+	}
+}
+
+// WriteTimeline writes the ruler ru and the annotated intervals below it
+// to a writer w.
+func WriteTimeline(w io.Writer, ru Ruler, intervals ...Interval) error {
+	return NewRenderer().WriteTimeline(w, ru, intervals...)
+}
+
+// WriteTimeline writes the ruler ru and the annotated intervals below it
+// to a writer w. Intervals are ranges of the columns of their start and
+// end values. Overlapping intervals, e.g. nested spans of a trace, are
+// rendered in separate lanes below each other. Intervals are assigned in
+// the order of their start values to the first lane with space.
+func (r *Renderer) WriteTimeline(w io.Writer, ru Ruler, intervals ...Interval) error {
+	sorted := slices.Clone(intervals)
+	slices.SortStableFunc(sorted, func(i1, i2 Interval) int {
+		switch {
+		case i1.Start < i2.Start:
+			return -1
+		case i1.Start > i2.Start:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	var lanes [][]*Annot
+	for _, i := range sorted {
+		a := ru.AnnotRange(i.Start, i.End, i.Lines...)
+		laneIdx := slices.IndexFunc(lanes, func(lane []*Annot) bool {
+			last := lane[len(lane)-1]
+			return max(last.Col, last.ColEnd) < a.Col
+		})
+		if laneIdx == -1 {
+			lanes = append(lanes, nil)
+			laneIdx = len(lanes) - 1
+		}
+		lanes[laneIdx] = append(lanes[laneIdx], a)
+	}
+
+	if _, err := fmt.Fprint(w, ru.draw(r.glyphs)); err != nil {
+		return err
+	}
+	for _, lane := range lanes {
+		if err := r.Write(w, lane...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package annot
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteTimeline(t *testing.T) {
+	tests := []struct {
+		name      string
+		ru        Ruler
+		intervals []Interval
+		wantW     string
+	}{
+		{
+			name: "no intervals",
+			ru:   Ruler{Min: 0, Max: 10, Width: 11},
+			wantW: `
+0        10
+├─────────┤
+`,
+		},
+		{
+			name: "sequential intervals in one lane",
+			ru:   DurationRuler(2*time.Second, 21, 2),
+			intervals: []Interval{
+				DurationInterval(1200*time.Millisecond, 2*time.Second, "compile"),
+				DurationInterval(0, time.Second, "parse"),
+			},
+			wantW: `
+0s        1s       2s
+├─────────┼─────────┤
+└────┬────┘ └───┬───┘
+     └─ parse   └─ compile
+`,
+		},
+		{
+			name: "nested intervals in separate lanes",
+			ru:   Ruler{Min: 0, Max: 20, Width: 21, Ticks: 2},
+			intervals: []Interval{
+				{Start: 0, End: 20, Lines: []string{"request"}},
+				{Start: 2, End: 8, Lines: []string{"query"}},
+				{Start: 10, End: 10, Lines: []string{"cache miss"}},
+			},
+			wantW: `
+0         10       20
+├─────────┼─────────┤
+└─────────┬─────────┘
+          └─ request
+  └──┬──┘ ↑
+     │    └─ cache miss
+     │
+     └─ query
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := WriteTimeline(w, tt.ru, tt.intervals...); err != nil {
+				t.Fatalf("WriteTimeline() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteTimeline() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestRenderer_WriteTimeline_theme(t *testing.T) {
+	w := &bytes.Buffer{}
+	err := NewRenderer(WithTheme(ThemeHeavy)).WriteTimeline(w, Ruler{Min: 0, Max: 10, Width: 11}, Interval{Start: 2, End: 6, Lines: []string{"run"}})
+	if err != nil {
+		t.Fatalf("WriteTimeline() unexpected error = %v", err)
+	}
+	want := `
+0        10
+┣━━━━━━━━━┫
+  ┗━┳━┛
+    ┗━ run
+`
+	if gotW := "\n" + w.String(); gotW != want {
+		t.Errorf("WriteTimeline() gotW = %v, want %v", gotW, want)
+	}
+}
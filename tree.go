@@ -0,0 +1,70 @@
+package annot
+
+import (
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// treeLine matches the indentation units, the branch and the name of a
+// line of the output of the tree command in UTF-8 and ASCII charsets.
+var treeLine = regexp.MustCompile(`^((?:[│|][ \x{00a0}]{3}|[ \x{00a0}]{4})*)([├└|` + "`" + `+\\][─-]{2}[ \x{00a0}])?(.*)$`)
+
+// WriteTree renders an ASCII tree, e.g. the output of the tree command,
+// with labels right of its nodes and writes it to a writer w. See
+// [Renderer.WriteTree] for the keys of labels.
+func WriteTree(w io.Writer, tree string, labels map[string][]string) error {
+	return NewRenderer().WriteTree(w, tree, labels)
+}
+
+// WriteTree renders an ASCII tree, e.g. the output of the tree command,
+// with labels right of its nodes and writes it to a writer w. The keys of
+// labels are the slash separated names of the nodes below the root, e.g.
+// "cmd/main.go", and "" for the root. The labels are aligned right of the
+// widest line like WriteVertical, so connectors never cross the branches
+// of the tree.
+//
+//	.
+//	├── cmd
+//	│   └── main.go ←─ entry point
+//	└── go.mod      ←─ module
+func (r *Renderer) WriteTree(w io.Writer, tree string, labels map[string][]string) error {
+	lines := strings.Split(strings.TrimSuffix(tree, "\n"), "\n")
+	paths := treePaths(lines)
+
+	annots := make([]*Annot, 0, len(labels))
+	for path, labelLines := range labels {
+		lineIdx, ok := paths[path]
+		if !ok {
+			return newUnknownNodeError(path)
+		}
+		annots = append(annots, &Annot{Col: lineIdx + r.origin, Lines: labelLines})
+	}
+	slices.SortFunc(annots, func(a1, a2 *Annot) int {
+		return a1.Col - a2.Col
+	})
+	return r.WriteVertical(w, lines, annots...)
+}
+
+// treePaths returns the line indices of the nodes of a tree by their
+// paths. The first line of a path wins.
+func treePaths(lines []string) map[string]int {
+	paths := map[string]int{}
+	var names []string
+	for lineIdx, l := range lines {
+		m := treeLine.FindStringSubmatch(l)
+		depth := 0
+		if m[2] != "" {
+			depth = len([]rune(m[1]))/4 + 1
+		}
+		name, _, _ := strings.Cut(m[3], " -> ")
+
+		names = append(names[:min(depth, len(names))], name)
+		path := strings.Join(names[1:], "/")
+		if _, ok := paths[path]; !ok {
+			paths[path] = lineIdx
+		}
+	}
+	return paths
+}
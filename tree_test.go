@@ -0,0 +1,79 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteTree(t *testing.T) {
+	tests := []struct {
+		name    string
+		tree    string
+		labels  map[string][]string
+		wantW   string
+		wantErr error
+	}{
+		{
+			name: "unicode tree",
+			tree: `.
+├── cmd
+│   └── main.go
+└── go.mod
+
+1 directory, 2 files
+`,
+			labels: map[string][]string{
+				"go.mod":      {"module"},
+				"cmd/main.go": {"entry point"},
+				"":            {"root"},
+			},
+			wantW: `
+.                    ←─ root
+├── cmd
+│   └── main.go      ←─ entry point
+└── go.mod           ←─ module
+
+1 directory, 2 files
+`,
+		},
+		{
+			name: "ascii tree with non-breaking spaces and symlink",
+			tree: ".\n|-- a\n|\u00a0\u00a0 `-- b -> ../b\n`-- c\n",
+			labels: map[string][]string{
+				"a/b": {"link"},
+			},
+			wantW: "\n" +
+				".\n" +
+				"|-- a\n" +
+				"|\u00a0\u00a0 `-- b -> ../b ←─ link\n" +
+				"`-- c\n",
+		},
+		{
+			name: "unknown node",
+			tree: ".\n└── a\n",
+			labels: map[string][]string{
+				"b": {"missing"},
+			},
+			wantErr: &UnknownNodeError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := WriteTree(w, tt.tree, tt.labels)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("WriteTree() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteTree() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteTree() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
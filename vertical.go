@@ -0,0 +1,130 @@
+package annot
+
+import (
+	"io"
+	"slices"
+	"strings"
+)
+
+// WriteVertical renders a vertical list of items, one item per row, with
+// annotations right of the items and writes it to a writer w. Col and
+// ColEnd of the annotations are indices of items instead of columns.
+//
+//	goroutine 1 [running]:
+//	main.get(...)           ←─ crashed here
+//	        /app/main.go:8  ┐
+//	main.main()             ├─ caller
+//	        /app/main.go:12 ┘
+func WriteVertical(w io.Writer, items []string, annots ...*Annot) error {
+	return NewRenderer().WriteVertical(w, items, annots...)
+}
+
+// WriteVertical renders a vertical list of items, one item per row, with
+// annotations right of the items and writes it to a writer w. Col and
+// ColEnd of the annotations are indices of items instead of columns. It
+// is the transposed layout of Write: the lines of an annotation start in
+// the row of its stem and annotations above other annotations move right
+// until their lines fit. WithConnector and WithWidth do not apply.
+func (r *Renderer) WriteVertical(w io.Writer, items []string, annots ...*Annot) error {
+	annots, skipped, err := r.arrange(annots)
+	if err != nil {
+		return err
+	}
+	for _, a := range annots {
+		if last := max(a.col, a.colEnd); last >= len(items) {
+			return newLineOutOfRangeError(last+r.origin, len(items))
+		}
+	}
+
+	rows := r.verticalRows(items, annots)
+	if err := (&Layout{r: r, rows: rows}).Write(w); err != nil {
+		return err
+	}
+	if len(skipped) != 0 {
+		return newSkippedError(skipped)
+	}
+	return nil
+}
+
+// verticalRows returns the rendered rows of the items and the arranged
+// annotations.
+func (r *Renderer) verticalRows(items []string, annots []*Annot) [][]Segment {
+	markerCol := 0
+	for _, item := range items {
+		markerCol = max(markerCol, r.stringWidth(item)+1)
+	}
+	minTextCol := markerCol + 3
+
+	widths := make([]int, len(annots))
+	for aIdx, a := range annots {
+		for _, l := range a.Lines {
+			widths[aIdx] = max(widths[aIdx], r.stringWidth(l))
+		}
+	}
+
+	// Start with the last annotation, which is always next to the
+	// items. An annotation moves right of every annotation below whose
+	// lines start in a row of its own lines.
+	textCols := make([]int, len(annots))
+	rowCount := len(items)
+	for aIdx := len(annots) - 1; 0 <= aIdx; aIdx-- {
+		a := annots[aIdx]
+		textCols[aIdx] = minTextCol
+		lastRow := a.pipeColIdx + max(len(a.Lines), 1) - 1
+		for bIdx := aIdx + 1; bIdx < len(annots) && annots[bIdx].pipeColIdx <= lastRow; bIdx++ {
+			textCols[aIdx] = max(textCols[aIdx], textCols[bIdx]+widths[bIdx]+1)
+		}
+		rowCount = max(rowCount, lastRow+1)
+	}
+
+	rows := make([][]Segment, rowCount)
+	for row, item := range items {
+		rows[row] = append(rows[row], Segment{Col: 0, Text: item, Kind: TextSegment, Index: -1})
+	}
+	for aIdx, a := range annots {
+		for row, glyph := range r.verticalMarkers(a) {
+			kind := RangeSegment
+			if a.colEnd == 0 {
+				kind = ArrowSegment
+			}
+			rows[row] = append(rows[row], Segment{Col: markerCol, Text: glyph, Kind: kind, Annot: a, Index: a.idx})
+		}
+
+		connector := strings.Repeat(r.glyphs.Horizontal, textCols[aIdx]-markerCol-2) + " "
+		rows[a.pipeColIdx] = append(rows[a.pipeColIdx],
+			Segment{Col: markerCol + 1, Text: connector, Kind: ConnectorSegment, Annot: a, Index: a.idx})
+		for lIdx, l := range a.Lines {
+			row := a.pipeColIdx + lIdx
+			rows[row] = appendText(rows[row], textCols[aIdx], l, a)
+		}
+	}
+
+	for _, segments := range rows {
+		slices.SortFunc(segments, func(s1, s2 Segment) int {
+			return s1.Col - s2.Col
+		})
+	}
+	return rows
+}
+
+// verticalMarkers returns the glyphs of the arrowhead or range of a by
+// row.
+func (r *Renderer) verticalMarkers(a *Annot) map[int]string {
+	if a.colEnd == 0 {
+		if r.noArrowheads {
+			return map[int]string{a.col: r.glyphs.Horizontal}
+		}
+		return map[int]string{a.col: r.glyphs.LeftArrowhead}
+	}
+
+	markers := map[int]string{a.col: r.glyphs.VerticalRangeTop, a.colEnd: r.glyphs.VerticalRangeBottom}
+	for row := a.col + 1; row < a.colEnd; row++ {
+		markers[row] = r.glyphs.Pipe
+	}
+	if a.col == a.pipeColIdx {
+		markers[a.col] = r.glyphs.VerticalTee
+	} else {
+		markers[a.pipeColIdx] = r.glyphs.VerticalRangeMid
+	}
+	return markers
+}
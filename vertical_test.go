@@ -0,0 +1,128 @@
+package annot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteVertical(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		items   []string
+		annots  []*Annot
+		wantW   string
+		wantErr error
+	}{
+		{
+			name:  "no annotations",
+			items: []string{"a", "bc"},
+			wantW: `
+a
+bc
+`,
+		},
+		{
+			name: "arrow and range",
+			items: []string{
+				"goroutine 1 [running]:",
+				"main.get(...)",
+				"        /app/main.go:8",
+				"main.main()",
+				"        /app/main.go:12",
+			},
+			annots: []*Annot{
+				{Col: 1, Lines: []string{"crashed here"}},
+				{Col: 2, ColEnd: 4, Lines: []string{"caller"}},
+			},
+			wantW: `
+goroutine 1 [running]:
+main.get(...)           ←─ crashed here
+        /app/main.go:8  ┐
+main.main()             ├─ caller
+        /app/main.go:12 ┘
+`,
+		},
+		{
+			name:  "lines of upper annotation move right",
+			items: []string{"a", "b", "c", "d"},
+			annots: []*Annot{
+				{Col: 0, ColEnd: 1, Lines: []string{"first", "second", "third"}},
+				{Col: 2, Lines: []string{"c"}},
+				{Col: 3, Lines: []string{"long line"}},
+			},
+			wantW: `
+a ┬─── first
+b ┘    second
+c ←─ c third
+d ←─ long line
+`,
+		},
+		{
+			name:  "overlapping lines",
+			items: []string{"a", "b", "c"},
+			annots: []*Annot{
+				{Col: 0, Lines: []string{"first", "second"}},
+				{Col: 1, Lines: []string{"b"}},
+				{Col: 2, Lines: []string{"c"}},
+			},
+			wantW: `
+a ←─── first
+b ←─ b second
+c ←─ c
+`,
+		},
+		{
+			name:  "lines after last item",
+			opts:  []Option{WithoutArrowheads()},
+			items: []string{"a", "b"},
+			annots: []*Annot{
+				{Col: 1, Lines: []string{"line1", "line2"}},
+			},
+			wantW: `
+a
+b ── line1
+     line2
+`,
+		},
+		{
+			name:  "one-based items",
+			opts:  []Option{WithOrigin(1)},
+			items: []string{"a", "b"},
+			annots: []*Annot{
+				{Col: 2, Lines: []string{"second"}},
+			},
+			wantW: `
+a
+b ←─ second
+`,
+		},
+		{
+			name:  "item out of range",
+			items: []string{"a"},
+			annots: []*Annot{
+				{Col: 0, ColEnd: 1},
+			},
+			wantErr: &LineOutOfRangeError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := NewRenderer(tt.opts...).WriteVertical(w, tt.items, tt.annots...)
+			if tt.wantErr != nil {
+				if !errors.Is(tt.wantErr, err) {
+					t.Errorf("WriteVertical() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteVertical() unexpected error = %v", err)
+			}
+			if gotW := "\n" + w.String(); gotW != tt.wantW {
+				t.Errorf("WriteVertical() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}